@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hocv/gin-swagger-gen/lib/file"
+)
+
+// FileName default name of cache file, in dir to parse
+const FileName = ".gin-swagger-gen.cache"
+
+// Cache index of files and generated comment of handlers from last run,
+// invalid if version differs
+type Cache struct {
+	path    string
+	Version string            `json:"version"`
+	Files   map[string]*Entry `json:"files"` // key: path of file
+}
+
+// Entry cache of file
+type Entry struct {
+	Hash     string             `json:"hash"` // hash of source
+	Index    file.Index         `json:"index"`
+	Handlers map[string]Handler `json:"handlers,omitempty"` // key: handler and route
+}
+
+// Handler generated comment of handler
+type Handler struct {
+	Deps    string          `json:"deps"` // hash of files the handler depends on
+	Comment json.RawMessage `json:"comment"`
+}
+
+// Load load cache from path, empty if not exists, broken or version differs
+func Load(path, version string) *Cache {
+	c := &Cache{path: path, Version: version, Files: map[string]*Entry{}}
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var old Cache
+	if err := json.Unmarshal(bs, &old); err != nil || old.Version != version || old.Files == nil {
+		return c
+	}
+	c.Files = old.Files
+	return c
+}
+
+// File index of file, false if hash differs
+func (c *Cache) File(path, hash string) (*file.Index, bool) {
+	e, ok := c.Files[path]
+	if !ok || e.Hash != hash {
+		return nil, false
+	}
+	idx := e.Index
+	return &idx, true
+}
+
+// Handler comment of handler in file, false if deps differs
+func (c *Cache) Handler(path, key, deps string) (json.RawMessage, bool) {
+	e, ok := c.Files[path]
+	if !ok {
+		return nil, false
+	}
+	h, ok := e.Handlers[key]
+	if !ok || h.Deps != deps {
+		return nil, false
+	}
+	return h.Comment, true
+}
+
+// Reset replace entries with files, handlers are dropped
+func (c *Cache) Reset(files []*file.File) {
+	c.Files = map[string]*Entry{}
+	for _, f := range files {
+		c.Files[f.Path()] = &Entry{Hash: f.Hash(), Index: f.Index()}
+	}
+}
+
+// SetHandler set comment of handler in file
+func (c *Cache) SetHandler(path, key string, h Handler) {
+	e, ok := c.Files[path]
+	if !ok {
+		return
+	}
+	if e.Handlers == nil {
+		e.Handlers = map[string]Handler{}
+	}
+	e.Handlers[key] = h
+}
+
+// Save write cache to file
+func (c *Cache) Save() error {
+	bs, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, bs, 0666)
+}
@@ -289,34 +289,85 @@ func BasicLitValue(basic interface{}) string {
 const (
 	jsonTag    = "json:\""
 	formTAg    = "form:\""
+	headerTag  = "header:\""
+	uriTag     = "uri:\""
 	bindingTAg = "binding:\""
 )
 
+// bindingFormats validator of binding tag to swagger format
+var bindingFormats = map[string]string{
+	"uuid":     "uuid",
+	"uuid3":    "uuid",
+	"uuid4":    "uuid",
+	"uuid5":    "uuid",
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+	"datetime": "date-time",
+}
+
 func GetTagBindingRequired(str string) bool {
-	return getTag(bindingTAg, str) == "required"
+	for _, v := range getTagValues(bindingTAg, str) {
+		if v == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTagBindingFormat format of binding validators.
+// e.g. binding:"required,uuid" -> uuid
+func GetTagBindingFormat(str string) string {
+	for _, v := range getTagValues(bindingTAg, str) {
+		if i := strings.Index(v, "="); i > 0 {
+			v = v[:i]
+		}
+		if f, ok := bindingFormats[v]; ok {
+			return f
+		}
+	}
+	return ""
 }
 
 func GetFormTag(str string) string {
 	return getTag(formTAg, str)
 }
 
+func GetHeaderTag(str string) string {
+	return getTag(headerTag, str)
+}
+
+func GetUriTag(str string) string {
+	return getTag(uriTag, str)
+}
+
 func GetJsonTag(str string) string {
 	return getTag(jsonTag, str)
 }
 
 func getTag(tagType string, str string) string {
+	vs := getTagValues(tagType, str)
+	if len(vs) == 0 {
+		return ""
+	}
+	return vs[0]
+}
+
+// getTagValues values of tag split by comma
+func getTagValues(tagType string, str string) []string {
 	idx := strings.Index(str, tagType)
 	if idx < 0 {
-		return ""
+		return nil
 	}
 	str = str[idx+len(tagType):]
 	idx = strings.Index(str, "\"")
-	str = str[:idx]
-	idx = strings.Index(str, ",")
 	if idx < 0 {
-		return str
+		return nil
 	}
-	return str[:idx]
+	return strings.Split(str[:idx], ",")
 }
 
 func SnakeCase(s string) string {
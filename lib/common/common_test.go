@@ -98,6 +98,27 @@ func TestGetJsonTag(t *testing.T) {
 	}
 }
 
+func TestGetTagBinding(t *testing.T) {
+	arr := []struct {
+		Tag      string
+		Required bool
+		Format   string
+	}{
+		{Tag: `uri:"id" binding:"required,uuid"`, Required: true, Format: "uuid"},
+		{Tag: `binding:"email,required"`, Required: true, Format: "email"},
+		{Tag: `binding:"max=10"`},
+		{Tag: `json:"id"`},
+	}
+	for _, bt := range arr {
+		if r := GetTagBindingRequired(bt.Tag); r != bt.Required {
+			t.Fatalf("%s -> required %t, but: %t", bt.Tag, bt.Required, r)
+		}
+		if f := GetTagBindingFormat(bt.Tag); f != bt.Format {
+			t.Fatalf("%s -> format %s, but: %s", bt.Tag, bt.Format, f)
+		}
+	}
+}
+
 func TestSnakeCase(t *testing.T) {
 	arr := []struct {
 		Src string
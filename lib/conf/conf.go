@@ -0,0 +1,147 @@
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hocv/gin-swagger-gen/lib/scan"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// FileNames name of config file in root dir, the first found is used
+var FileNames = []string{
+	".gin-swagger-gen.yaml",
+	".gin-swagger-gen.yml",
+	".gin-swagger-gen.toml",
+}
+
+// tag strategy of handle
+const (
+	TagGroup   = "group"   // base path of route group, default
+	TagPackage = "package" // package name of handle
+	TagPath    = "path"    // first segment of route path
+	TagNone    = "none"    // no tag
+)
+
+// handling of functions with @Router not routed
+const (
+	OrphansReport = "report" // report them
+	OrphansStrip  = "strip"  // strip swag lines, keep description
+)
+
+// Config project config
+type Config struct {
+	Func      string            `yaml:"func" toml:"func"`           // specify the function to add comment
+	Include   []string          `yaml:"include" toml:"include"`     // glob patterns to scan, relative to root. e.g. api/**
+	Exclude   []string          `yaml:"exclude" toml:"exclude"`     // glob patterns not to scan, relative to root. e.g. **/mock
+	Tests     bool              `yaml:"tests" toml:"tests"`         // scan _test.go files
+	Generated bool              `yaml:"generated" toml:"generated"` // comment generated files, only read for types if false
+	Tags      []string          `yaml:"tags" toml:"tags"`           // build tags
+	Workers   int               `yaml:"workers" toml:"workers"`     // goroutines parsing files, NumCPU if 0
+	Info      map[string]string `yaml:"info" toml:"info"`           // general api info. e.g. title, host
+	Security  []string          `yaml:"security" toml:"security"`   // e.g. apikey ApiKeyAuth in=header name=Authorization
+	Wrappers  []Wrapper         `yaml:"wrappers" toml:"wrappers"`   // response wrapper functions
+	Tag       string            `yaml:"tag" toml:"tag"`             // tag strategy: group, package, path, none
+	StaticTag string            `yaml:"staticTag" toml:"staticTag"` // tag of static file routes, default static
+	Success   []int             `yaml:"success" toml:"success"`     // status codes of @Success, default 200
+	Outputs   []Output          `yaml:"outputs" toml:"outputs"`     // documents written after comments saved
+	Orphans   string            `yaml:"orphans" toml:"orphans"`     // functions with @Router not routed: report, strip
+}
+
+// Wrapper function writing response. e.g.
+// func OK(c *gin.Context, data interface{}) { c.JSON(200, Resp{Data: data}) }
+type Wrapper struct {
+	Func    string `yaml:"func" toml:"func"`       // e.g. response.OK
+	Code    int    `yaml:"code" toml:"code"`       // status code, default 200
+	Type    string `yaml:"type" toml:"type"`       // e.g. response.Resp{data=%s}, %s is type of arg
+	Arg     *int   `yaml:"arg" toml:"arg"`         // index of data arg, last one if not set
+	Produce string `yaml:"produce" toml:"produce"` // default json
+}
+
+// DataArg index of data arg in call with n args, last one if not set
+func (w Wrapper) DataArg(n int) int {
+	if w.Arg == nil {
+		return n - 1
+	}
+	return *w.Arg
+}
+
+// Output document file
+type Output struct {
+	Path    string `yaml:"path" toml:"path"`       // .json, .yaml
+	Version string `yaml:"version" toml:"version"` // 2.0, 3.0, 3.1, default 2.0
+}
+
+// Load load config file in root dir, empty config if not exists
+func Load(root string) (*Config, error) {
+	for _, name := range FileNames {
+		path := filepath.Join(root, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		return LoadFile(path)
+	}
+	return &Config{}, nil
+}
+
+// LoadFile load config file, toml if file ext is .toml, otherwise yaml
+func LoadFile(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read config")
+	}
+
+	cfg := &Config{}
+	if strings.HasSuffix(path, ".toml") {
+		err = toml.Unmarshal(data, cfg)
+	} else {
+		err = yaml.UnmarshalStrict(data, cfg)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse config %s", path)
+	}
+	return cfg, nil
+}
+
+// ScanOptions options of scanning files
+func (c *Config) ScanOptions() scan.Options {
+	return scan.Options{
+		Include: c.Include,
+		Exclude: c.Exclude,
+		Tests:   c.Tests,
+		Tags:    c.Tags,
+	}
+}
+
+// IsSuccess status code is success
+func (c *Config) IsSuccess(code int) bool {
+	if len(c.Success) == 0 {
+		return code == 200
+	}
+	for _, s := range c.Success {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrapper wrapper of function. e.g. response.OK
+func (c *Config) Wrapper(fn string) (Wrapper, bool) {
+	for _, w := range c.Wrappers {
+		if w.Func == fn {
+			if w.Code == 0 {
+				w.Code = 200
+			}
+			if len(w.Produce) == 0 {
+				w.Produce = "json"
+			}
+			return w, true
+		}
+	}
+	return Wrapper{}, false
+}
@@ -0,0 +1,89 @@
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// no config file
+	cfg, err := Load(dir)
+	if err != nil || !cfg.IsSuccess(200) || cfg.IsSuccess(201) {
+		t.Fatal(cfg, err)
+	}
+
+	files := map[string]string{
+		".gin-swagger-gen.yaml": `
+exclude: [vendor, internal/mock]
+info:
+  title: Book API
+tag: package
+success: [200, 201]
+wrappers:
+  - func: response.OK
+    type: response.Resp{data=%s}
+    arg: 1
+  - func: response.Fail
+outputs:
+  - path: docs/openapi.yaml
+    version: "3.0"
+`,
+		".gin-swagger-gen.toml": `
+exclude = ["vendor", "internal/mock"]
+tag = "package"
+success = [200, 201]
+
+[info]
+title = "Book API"
+
+[[wrappers]]
+func = "response.OK"
+type = "response.Resp{data=%s}"
+arg = 1
+
+[[wrappers]]
+func = "response.Fail"
+
+[[outputs]]
+path = "docs/openapi.yaml"
+version = "3.0"
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cfg.Exclude) != 2 || cfg.Info["title"] != "Book API" || cfg.Tag != TagPackage {
+			t.Fatalf("%s: %+v", name, cfg)
+		}
+		if !cfg.IsSuccess(201) || len(cfg.Outputs) != 1 || cfg.Outputs[0].Version != "3.0" {
+			t.Fatalf("%s: %+v", name, cfg)
+		}
+		w, ok := cfg.Wrapper("response.OK")
+		if !ok || w.Code != 200 || w.Produce != "json" || w.DataArg(3) != 1 {
+			t.Fatalf("%s: %+v", name, w)
+		}
+		// data arg defaults to the last one
+		if w, ok := cfg.Wrapper("response.Fail"); !ok || w.Arg != nil || w.DataArg(3) != 2 {
+			t.Fatalf("%s: %+v", name, w)
+		}
+	}
+
+	// yaml is preferred
+	if cfg, err := Load(dir); err != nil || cfg.Tag != TagPackage {
+		t.Fatal(cfg, err)
+	}
+}
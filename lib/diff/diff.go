@@ -0,0 +1,166 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context lines around changes
+const context = 3
+
+type edit struct {
+	kind byte // ' ' equal, '-' delete, '+' insert
+	line string
+	a, b int // index of line in a and b before this edit
+}
+
+// Unified unified diff of a and b, empty if no difference
+func Unified(nameA, nameB, a, b string) string {
+	if a == b {
+		return ""
+	}
+	es := edits(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", nameA, nameB)
+	for _, h := range hunks(es) {
+		writeHunk(&sb, es[h[0]:h[1]])
+	}
+	return sb.String()
+}
+
+// splitLines split text to lines, keep line ending
+func splitLines(s string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// edits shortest edit script from a to b, Myers' algorithm
+func edits(a, b []string) []edit {
+	n, m := len(a), len(b)
+	max := n + m
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+search:
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	var es []edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[max+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			es = append(es, edit{kind: ' ', line: a[x], a: x, b: y})
+		}
+		if d > 0 {
+			if x == prevX {
+				es = append(es, edit{kind: '+', line: b[prevY], a: x, b: prevY})
+			} else {
+				es = append(es, edit{kind: '-', line: a[prevX], a: prevX, b: y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(es)-1; i < j; i, j = i+1, j-1 {
+		es[i], es[j] = es[j], es[i]
+	}
+	return es
+}
+
+// hunks ranges of edits, changes closer than 2*context are merged
+func hunks(es []edit) (hs [][2]int) {
+	for i := 0; i < len(es); i++ {
+		if es[i].kind == ' ' {
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(es); j++ {
+			if es[j].kind != ' ' {
+				end = j
+				continue
+			}
+			if j-end > 2*context {
+				break
+			}
+		}
+		end += context + 1
+		if end > len(es) {
+			end = len(es)
+		}
+		if len(hs) > 0 && hs[len(hs)-1][1] >= start {
+			hs[len(hs)-1][1] = end
+		} else {
+			hs = append(hs, [2]int{start, end})
+		}
+		i = end - 1
+	}
+	return
+}
+
+func writeHunk(sb *strings.Builder, es []edit) {
+	var countA, countB int
+	for _, e := range es {
+		if e.kind != '+' {
+			countA++
+		}
+		if e.kind != '-' {
+			countB++
+		}
+	}
+	startA, startB := es[0].a, es[0].b
+	if countA > 0 {
+		startA++
+	}
+	if countB > 0 {
+		startB++
+	}
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", startA, countA, startB, countB)
+	for _, e := range es {
+		sb.WriteByte(e.kind)
+		sb.WriteString(e.line)
+		if !strings.HasSuffix(e.line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
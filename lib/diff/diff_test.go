@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	a := "package a\n\n// @Summary get\n// @Param id path string true \"id\"\nfunc get() {}\n"
+	b := "package a\n\n// @Summary get\n// @Param id path integer true \"id\"\n// @Success 200 {string} string\nfunc get() {}\n"
+	expect := `--- a/a.go
++++ b/a.go
+@@ -1,5 +1,6 @@
+ package a
+ 
+ // @Summary get
+-// @Param id path string true "id"
++// @Param id path integer true "id"
++// @Success 200 {string} string
+ func get() {}
+`
+	if d := Unified("a/a.go", "b/a.go", a, b); d != expect {
+		t.Fatalf("diff wrong:\n%s", d)
+	}
+}
+
+func TestUnifiedHunks(t *testing.T) {
+	var a, b string
+	for i := 0; i < 20; i++ {
+		line := string(rune('a'+i)) + "\n"
+		a += line
+		if i == 2 || i == 17 {
+			line = "x\n"
+		}
+		b += line
+	}
+	d := Unified("a", "b", a, b)
+	expect := `--- a
++++ b
+@@ -1,6 +1,6 @@
+ a
+ b
+-c
++x
+ d
+ e
+ f
+@@ -15,6 +15,6 @@
+ o
+ p
+ q
+-r
++x
+ s
+ t
+`
+	if d != expect {
+		t.Fatalf("diff wrong:\n%s", d)
+	}
+	if Unified("a", "b", a, a) != "" {
+		t.Fatal("same text should no diff")
+	}
+}
@@ -1,76 +1,282 @@
 package file
 
 import (
+	"bufio"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"go/ast"
 	"go/parser"
 	"go/token"
 	"io/ioutil"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/dave/dst"
 	"github.com/dave/dst/decorator"
 	"github.com/hocv/gin-swagger-gen/lib/common"
+	"github.com/hocv/gin-swagger-gen/lib/diff"
 )
 
 // File file
 type File struct {
 	dirty      bool
+	generated  bool                       // "// Code generated ... DO NOT EDIT." before package clause
 	src        string                     // file name or source
+	orig       []byte                     // original source
+	hash       string                     // hash of orig
 	file       *dst.File                  // dst file
+	fset       *token.FileSet             // file set of ast
+	nodes      map[dst.Node]ast.Node      // dst node to ast node
 	pkg        string                     // package name
+	pkgPath    string                     // import path of package
 	globalVars map[string]string          // global vars
+	values     map[string]dst.Expr        // values of global vars
 	imports    map[string]string          // import
 	types      map[string]string          // types
-	funcs      map[string]*dst.FuncDecl   // functions
+	funcs      map[string]*dst.FuncDecl   // functions and methods
+	methods    map[string]*dst.FuncDecl   // methods, key: Recv.Name
 	structs    map[string]*dst.StructType // structs
+	index      *Index                     // index of unchanged file, dst is parsed on demand
+}
+
+// Index names and types of file, enough to resolve lookups without dst
+type Index struct {
+	Pkg        string            `json:"pkg"`
+	Imports    map[string]string `json:"imports"`
+	GlobalVars map[string]string `json:"globalVars"`
+	Types      map[string]string `json:"types"`
+	Funcs      []string          `json:"funcs"` // functions, Recv.Name for methods
+	Structs    []string          `json:"structs"`
+}
+
+// Hash hash of source
+func Hash(src []byte) string {
+	sum := sha256.Sum256(src)
+	return hex.EncodeToString(sum[:])
 }
 
 // New file. src : path of go file or source
 func New(src string) (*File, error) {
-	fileSet := token.NewFileSet()
-	var file *dst.File
-	var err error
-
+	orig := []byte(src)
 	if filepath.Ext(src) == ".go" {
-		file, err = decorator.ParseFile(fileSet, src, nil, parser.ParseComments)
-	} else {
-		file, err = decorator.ParseFile(fileSet, "", src, parser.ParseComments)
-	}
-
-	if err != nil {
-		return nil, err
+		var err error
+		if orig, err = ioutil.ReadFile(src); err != nil {
+			return nil, err
+		}
 	}
+	return Load(src, orig, nil)
+}
 
+// Load file of path with source, dst is parsed on first lookup needing it if idx is not nil
+func Load(path string, orig []byte, idx *Index) (*File, error) {
 	f := &File{
-		src:        src,
-		file:       file,
-		dirty:      false,
+		src:        path,
+		orig:       orig,
+		hash:       Hash(orig),
+		generated:  isGenerated(orig),
 		globalVars: map[string]string{},
+		values:     map[string]dst.Expr{},
 		imports:    map[string]string{},
 		types:      map[string]string{},
 		funcs:      map[string]*dst.FuncDecl{},
+		methods:    map[string]*dst.FuncDecl{},
 		structs:    map[string]*dst.StructType{},
 	}
-	f.parse()
+	if idx == nil {
+		return f, f.load()
+	}
+	f.index = idx
+	f.pkg = idx.Pkg
+	for k, v := range idx.Imports {
+		f.imports[k] = v
+	}
+	for k, v := range idx.GlobalVars {
+		f.globalVars[k] = v
+	}
+	for k, v := range idx.Types {
+		f.types[k] = v
+	}
 	return f, nil
 }
 
+// load parse source to dst if not parsed
+func (f *File) load() error {
+	if f.file != nil {
+		return nil
+	}
+	fileSet := token.NewFileSet()
+	dec := decorator.NewDecorator(fileSet)
+	name := f.src
+	if filepath.Ext(name) != ".go" {
+		name = ""
+	}
+	file, err := dec.ParseFile(name, f.orig, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	f.file = file
+	f.fset = fileSet
+	f.nodes = dec.Ast.Nodes
+	f.parse()
+	return nil
+}
+
+// lookup load dst if index of file has any of names
+func (f *File) lookup(names ...string) bool {
+	if f.file != nil {
+		return true
+	}
+	if f.index != nil && !f.index.has(names) {
+		return false
+	}
+	return f.load() == nil
+}
+
+func (idx *Index) has(names []string) bool {
+	for _, name := range names {
+		for _, fn := range idx.Funcs {
+			if fn == name || strings.HasSuffix(fn, "."+name) {
+				return true
+			}
+		}
+		for _, st := range idx.Structs {
+			if st == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Index index of file
+func (f *File) Index() Index {
+	if f.file == nil && f.index != nil {
+		return *f.index
+	}
+	idx := Index{
+		Pkg:        f.pkg,
+		Imports:    f.imports,
+		GlobalVars: f.globalVars,
+		Types:      f.types,
+	}
+	for name, fd := range f.funcs {
+		if fd.Recv == nil {
+			idx.Funcs = append(idx.Funcs, name)
+		}
+	}
+	for name := range f.methods {
+		idx.Funcs = append(idx.Funcs, name)
+	}
+	for name := range f.structs {
+		idx.Structs = append(idx.Structs, name)
+	}
+	sort.Strings(idx.Funcs)
+	sort.Strings(idx.Structs)
+	return idx
+}
+
+var generatedReg = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// isGenerated source has comment "// Code generated ... DO NOT EDIT." before package clause
+func isGenerated(src []byte) bool {
+	sc := bufio.NewScanner(bytes.NewReader(src))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "package ") {
+			return false
+		}
+		if generatedReg.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// Generated file is generated. e.g. protobuf
+func (f *File) Generated() bool {
+	return f.generated
+}
+
+// Hash hash of source, the saved one after Save
+func (f *File) Hash() string {
+	return f.hash
+}
+
 func (f *File) Dirty() {
 	f.dirty = true
 }
 
-// Save file
+// Modified dst is changed and not saved
+func (f *File) Modified() bool {
+	return f.dirty
+}
+
+// Save file, parsed again if changed
 func (f *File) Save() error {
 	if !f.dirty || filepath.Ext(f.src) != ".go" {
 		return nil
 	}
+	bs, err := f.print()
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(f.src, bs, 0666); err != nil {
+		return err
+	}
+	// parsed again so that offsets of nodes are of saved source, e.g. for go/types
+	saved, err := Load(f.src, bs, nil)
+	if err != nil {
+		return err
+	}
+	saved.pkgPath = f.pkgPath
+	*f = *saved
+	return nil
+}
+
+// Diff unified diff between original source and dirty file, empty if not dirty
+func (f *File) Diff() (string, error) {
+	if !f.dirty {
+		return "", nil
+	}
+	bs, err := f.print()
+	if err != nil {
+		return "", err
+	}
+	name := filepath.ToSlash(f.src)
+	return diff.Unified("a/"+name, "b/"+name, string(f.orig), string(bs)), nil
+}
+
+// Span offsets of node in source
+func (f *File) Span(node dst.Node) (start, end int, ok bool) {
+	if f.file == nil {
+		return 0, 0, false
+	}
+	n, ok := f.nodes[node]
+	if !ok || !n.Pos().IsValid() {
+		return 0, 0, false
+	}
+	tf := f.fset.File(n.Pos())
+	if tf == nil {
+		return 0, 0, false
+	}
+	return tf.Offset(n.Pos()), tf.Offset(n.End()), true
+}
+
+func (f *File) print() ([]byte, error) {
 	var buf bytes.Buffer
 	if err := decorator.Fprint(&buf, f.file); err != nil {
-		return err
+		return nil, err
 	}
-	return ioutil.WriteFile(f.src, buf.Bytes(), 0666)
+	return buf.Bytes(), nil
+}
+
+// Path file name or source
+func (f *File) Path() string {
+	return f.src
 }
 
 // Pkg package name
@@ -78,6 +284,19 @@ func (f *File) Pkg() string {
 	return f.pkg
 }
 
+// SetPkgPath set import path of package
+func (f *File) SetPkgPath(path string) {
+	f.pkgPath = path
+}
+
+// PkgPath import path of package, package name if not in module
+func (f *File) PkgPath() string {
+	if len(f.pkgPath) == 0 {
+		return f.pkg
+	}
+	return f.pkgPath
+}
+
 func (f *File) parse() {
 	f.pkg = f.file.Name.String()
 
@@ -89,7 +308,16 @@ func (f *File) parse() {
 		switch decl.(type) {
 		case *dst.FuncDecl:
 			fn := decl.(*dst.FuncDecl)
-			f.funcs[fn.Name.String()] = fn
+			name := fn.Name.String()
+			if fn.Recv != nil && len(fn.Recv.List) > 0 {
+				recv := strings.Trim(common.ToStr(fn.Recv.List[0].Type), "*")
+				f.methods[fmt.Sprintf("%s.%s", recv, name)] = fn
+				// function first if same name
+				if fd, ok := f.funcs[name]; ok && fd.Recv == nil {
+					continue
+				}
+			}
+			f.funcs[name] = fn
 		case *dst.GenDecl:
 			gd := decl.(*dst.GenDecl)
 			for _, spec := range gd.Specs {
@@ -117,6 +345,12 @@ func (f *File) parse() {
 					for k, v := range common.GetVars(gd) {
 						f.globalVars[k] = v
 					}
+					vs := spec.(*dst.ValueSpec)
+					if len(vs.Values) == len(vs.Names) {
+						for i, name := range vs.Names {
+							f.values[name.Name] = vs.Values[i]
+						}
+					}
 				}
 			}
 		}
@@ -125,6 +359,9 @@ func (f *File) parse() {
 
 // Func search function with name
 func (f *File) Func(name string) (*dst.FuncDecl, error) {
+	if !f.lookup(name) {
+		return nil, common.ErrNotFind
+	}
 	fd, ok := f.funcs[name]
 	if !ok {
 		return nil, common.ErrNotFind
@@ -134,6 +371,9 @@ func (f *File) Func(name string) (*dst.FuncDecl, error) {
 
 // FuncWithParam search functions with param
 func (f *File) FuncWithParam(param string) (fds []*dst.FuncDecl) {
+	if f.load() != nil {
+		return
+	}
 	for _, fd := range f.funcs {
 		ps := common.GetFuncParamByType(fd, param)
 		if len(ps) > 0 {
@@ -144,9 +384,20 @@ func (f *File) FuncWithParam(param string) (fds []*dst.FuncDecl) {
 	return
 }
 
-// FuncWithSelector search functions that contain this expr
+// FuncWithSelector search functions that contain this expr, in order of source
 func (f *File) FuncWithSelector(expr string) (fds []*dst.FuncDecl) {
-	for _, fd := range f.funcs {
+	// no need to parse file without the expr
+	if f.file == nil && !bytes.Contains(f.orig, []byte(expr)) {
+		return
+	}
+	if f.load() != nil {
+		return
+	}
+	for _, decl := range f.file.Decls {
+		fd, ok := decl.(*dst.FuncDecl)
+		if !ok || f.funcs[fd.Name.Name] != fd {
+			continue
+		}
 		for _, stmt := range fd.Body.List {
 			if common.CheckSelectorExpr(stmt, expr) {
 				fds = append(fds, fd)
@@ -157,24 +408,57 @@ func (f *File) FuncWithSelector(expr string) (fds []*dst.FuncDecl) {
 	return
 }
 
+// FuncWithComment search functions and methods with attribute in comment, case insensitive. e.g. @Router
+func (f *File) FuncWithComment(attr string) (fds []*dst.FuncDecl) {
+	if f.file == nil && !bytes.Contains(bytes.ToLower(f.orig), []byte(strings.ToLower(attr))) {
+		return
+	}
+	if f.load() != nil {
+		return
+	}
+	for _, decl := range f.file.Decls {
+		fd, ok := decl.(*dst.FuncDecl)
+		if !ok {
+			continue
+		}
+		for _, line := range fd.Decs.Start.All() {
+			fields := strings.Fields(strings.TrimPrefix(line, "//"))
+			if len(fields) > 0 && strings.EqualFold(fields[0], attr) {
+				fds = append(fds, fd)
+				break
+			}
+		}
+	}
+	return
+}
+
+// FuncWithRecv search method of recv, or function without recv if recvName is empty
 func (f *File) FuncWithRecv(fnName, recvName string) (*dst.FuncDecl, error) {
-	fd, ok := f.funcs[fnName]
-	if !ok {
+	if !f.lookup(fnName) {
 		return nil, common.ErrNotFind
 	}
+	if len(recvName) == 0 {
+		fd, ok := f.funcs[fnName]
+		if !ok || fd.Recv != nil {
+			return nil, common.ErrNotFind
+		}
+		return fd, nil
+	}
 
-	if fd.Recv == nil && len(recvName) > 0 {
+	fd, ok := f.methods[fmt.Sprintf("%s.%s", strings.Trim(recvName, "*"), fnName)]
+	if !ok {
 		return nil, common.ErrNotFind
 	}
+	return fd, nil
+}
 
-	for _, field := range fd.Recv.List {
-		ft := strings.Trim(common.ToStr(field.Type), "*")
-		if ft == recvName {
-			return fd, nil
-		}
+// Imports import paths of file
+func (f *File) Imports() (paths []string) {
+	for path := range f.imports {
+		paths = append(paths, strings.Trim(path, "\""))
 	}
-
-	return nil, common.ErrNotFind
+	sort.Strings(paths)
+	return
 }
 
 func (f *File) Imported(path string) (string, bool) {
@@ -183,6 +467,21 @@ func (f *File) Imported(path string) (string, bool) {
 	return alias, ok
 }
 
+// ImportPath import path of the package imported as name,
+// pkgName return package name of path when imported without alias
+func (f *File) ImportPath(name string, pkgName func(path string) string) (string, bool) {
+	for path, alias := range f.imports {
+		path = strings.Trim(path, "\"")
+		if alias == "<nil>" {
+			alias = pkgName(path)
+		}
+		if alias == name {
+			return path, true
+		}
+	}
+	return "", false
+}
+
 func (f *File) DefaultImport(path string, value string) (string, bool) {
 	path = fmt.Sprintf("\"%s\"", path)
 	alias, ok := f.imports[path]
@@ -201,10 +500,31 @@ func (f *File) GlobalVars() map[string]string {
 	return f.globalVars
 }
 
+// GlobalValue value of global var. e.g. var routes = []Route{...}
+func (f *File) GlobalValue(name string) (dst.Expr, bool) {
+	if _, ok := f.globalVars[name]; !ok || f.load() != nil {
+		return nil, false
+	}
+	v, ok := f.values[name]
+	return v, ok
+}
+
 func (f *File) Struct(name string) (*dst.StructType, error) {
+	if !f.lookup(name) {
+		return nil, common.ErrNotFind
+	}
 	st, ok := f.structs[name]
 	if !ok {
 		return nil, common.ErrNotFind
 	}
 	return st, nil
 }
+
+// Type search named type, return underlying type. e.g. type Strs []string
+func (f *File) Type(name string) (string, error) {
+	t, ok := f.types[name]
+	if !ok {
+		return "", common.ErrNotFind
+	}
+	return t, nil
+}
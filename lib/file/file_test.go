@@ -1,7 +1,11 @@
 package file
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/dave/dst"
 )
 
 func TestAst_Struct(t *testing.T) {
@@ -51,3 +55,96 @@ func Test() {
 		return
 	}
 }
+
+func TestAst_Diff(t *testing.T) {
+	code := `package test
+
+func Test() {
+}
+`
+	a, err := New(code)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if d, _ := a.Diff(); len(d) > 0 {
+		t.Fatal("not dirty file should no diff")
+	}
+
+	fd, err := a.Func("Test")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	fd.Decs.Start.Append("// @Summary Test")
+	a.Dirty()
+	d, err := a.Diff()
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if !strings.Contains(d, "+// @Summary Test\n") {
+		t.Fatalf("diff wrong: %s", d)
+	}
+}
+
+func TestLoadIndex(t *testing.T) {
+	code := `
+package test
+
+type User struct {
+	Name string
+}
+
+func (u *User) Get() {}
+
+func List() {}
+`
+	f, err := New(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := f.Index()
+	lazy, err := Load(code, []byte(code), &idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lazy.Struct("Order"); err == nil || lazy.file != nil {
+		t.Fatal("should not parse file without Order")
+	}
+	if _, err := lazy.FuncWithRecv("Get", "*User"); err != nil || lazy.file == nil {
+		t.Fatal("should parse file with User.Get")
+	}
+	if fmt.Sprint(lazy.Index()) != fmt.Sprint(idx) {
+		t.Fatalf("%v, want %v", lazy.Index(), idx)
+	}
+}
+
+func TestGlobalValue(t *testing.T) {
+	code := `
+package test
+
+var routes = []Route{{"GET", "/users", list}}
+
+var count int
+`
+	f, err := New(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := f.Index()
+	lazy, err := Load(code, []byte(code), &idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lazy.GlobalValue("count"); ok {
+		t.Fatal("count has no value")
+	}
+	v, ok := lazy.GlobalValue("routes")
+	if !ok {
+		t.Fatal("value of routes not found")
+	}
+	if lit, ok := v.(*dst.CompositeLit); !ok || len(lit.Elts) != 1 {
+		t.Fatalf("%#v, want composite literal of 1 element", v)
+	}
+}
@@ -2,6 +2,8 @@ package pkg
 
 import (
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 
 	"github.com/dave/dst"
@@ -10,23 +12,47 @@ import (
 )
 
 type Pkg struct {
-	Name  string
+	Name  string // package name
+	Path  string // import path, package name if not in module
 	files []*file.File
 }
 
-func New(name string) *Pkg {
-	return &Pkg{Name: name}
+func New(path, name string) *Pkg {
+	return &Pkg{Path: path, Name: name}
 }
 
 func (p *Pkg) AddFile(files ...*file.File) {
 	for _, f := range files {
-		if f.Pkg() != p.Name {
+		if f.PkgPath() != p.Path {
 			continue
 		}
 		p.files = append(p.files, f)
 	}
 }
 
+// RemoveFile remove file of path
+func (p *Pkg) RemoveFile(path string) {
+	var files []*file.File
+	for _, f := range p.files {
+		if f.Path() != path {
+			files = append(files, f)
+		}
+	}
+	p.files = files
+}
+
+// SortFiles sort files by path, the order of scanning
+func (p *Pkg) SortFiles() {
+	sort.SliceStable(p.files, func(i, j int) bool {
+		return p.files[i].Path() < p.files[j].Path()
+	})
+}
+
+// Files files of package, in order of added
+func (p *Pkg) Files() []*file.File {
+	return p.files
+}
+
 // Func search function by name
 func (p *Pkg) GetFunc(name string) map[*file.File]*dst.FuncDecl {
 	af := make(map[*file.File]*dst.FuncDecl)
@@ -40,6 +66,34 @@ func (p *Pkg) GetFunc(name string) map[*file.File]*dst.FuncDecl {
 	return af
 }
 
+// Declares whether name is declared at package level. e.g. function, var, const, type
+func (p *Pkg) Declares(name string) bool {
+	if len(p.GetMethod(name, "")) > 0 {
+		return true
+	}
+	if _, ok := p.GetGlobalVar()[name]; ok {
+		return true
+	}
+	if _, err := p.GetStruct(name); err == nil {
+		return true
+	}
+	_, err := p.GetType(name)
+	return err == nil
+}
+
+// GetMethod search method of recv, or function without recv if recv is empty
+func (p *Pkg) GetMethod(name, recv string) map[*file.File]*dst.FuncDecl {
+	af := make(map[*file.File]*dst.FuncDecl)
+	for _, a := range p.files {
+		fd, err := a.FuncWithRecv(name, recv)
+		if err != nil {
+			continue
+		}
+		af[a] = fd
+	}
+	return af
+}
+
 func (p *Pkg) GetFuncWithSelector(expr string) map[*file.File][]*dst.FuncDecl {
 	af := make(map[*file.File][]*dst.FuncDecl)
 	for _, a := range p.files {
@@ -114,6 +168,16 @@ func (p *Pkg) GetGlobalVar() map[string]string {
 	return vars
 }
 
+// GetGlobalValue value of global var
+func (p *Pkg) GetGlobalValue(name string) (dst.Expr, bool) {
+	for _, a := range p.files {
+		if v, ok := a.GlobalValue(name); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
 func (p *Pkg) GetStruct(name string) (*dst.StructType, error) {
 	for _, a := range p.files {
 		stru, err := a.Struct(name)
@@ -124,6 +188,16 @@ func (p *Pkg) GetStruct(name string) (*dst.StructType, error) {
 	return nil, common.ErrNotFind
 }
 
+func (p *Pkg) GetType(name string) (string, error) {
+	for _, a := range p.files {
+		t, err := a.Type(name)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return "", common.ErrNotFind
+}
+
 func (p *Pkg) GetStructFieldType(structName, fieldName string) (string, bool) {
 	stru, err := p.GetStruct(structName)
 	if err != nil {
@@ -152,6 +226,16 @@ func (p *Pkg) GetImported(path string) (string, error) {
 	return "", common.ErrNotFind
 }
 
+// ImportPath import path of the package imported as name in files of package
+func (p *Pkg) ImportPath(name string, pkgName func(path string) string) (string, bool) {
+	for _, a := range p.files {
+		if path, ok := a.ImportPath(name, pkgName); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
 func (p *Pkg) GetDefaultImported(path, value string) (string, error) {
 	for _, a := range p.files {
 		alias, ok := a.DefaultImport(path, value)
@@ -171,3 +255,21 @@ func (p *Pkg) Save() error {
 	}
 	return nil
 }
+
+// Diff write unified diff of dirty files
+func (p *Pkg) Diff(w io.Writer) error {
+	files := append([]*file.File(nil), p.files...)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path() < files[j].Path()
+	})
+	for _, a := range files {
+		d, err := a.Diff()
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
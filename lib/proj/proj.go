@@ -2,88 +2,474 @@ package proj
 
 import (
 	"fmt"
+	"go/types"
+	"io"
 	"io/ioutil"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/dave/dst"
+	"github.com/hocv/gin-swagger-gen/lib/cache"
 	"github.com/hocv/gin-swagger-gen/lib/common"
 	"github.com/hocv/gin-swagger-gen/lib/file"
 	"github.com/hocv/gin-swagger-gen/lib/pkg"
+	"github.com/hocv/gin-swagger-gen/lib/scan"
+	"github.com/hocv/gin-swagger-gen/lib/typed"
 )
 
 type Proj struct {
-	mtx  sync.Mutex
-	pkgs map[string]*pkg.Pkg
+	mtx     sync.Mutex
+	pkgs    map[string]*pkg.Pkg // key: import path, package name if not in module
+	modules map[string]module   // key: dir
+	types   *typed.Info         // exact type info, nil if not loaded
+	workers int                 // number of goroutines parsing files, NumCPU if less than 1
+	cache   *cache.Cache        // index of unchanged files, nil if not used
+}
+
+// module go module contain the dir
+type module struct {
+	root string // dir of go.mod
+	path string // module path
 }
 
 func New() *Proj {
 	return &Proj{
-		pkgs: map[string]*pkg.Pkg{},
+		pkgs:    map[string]*pkg.Pkg{},
+		modules: map[string]module{},
 	}
 }
 
-func (proj *Proj) ScanDir(dir string) {
-	files := scanDir(dir)
+// SetWorkers set number of goroutines parsing files, NumCPU if less than 1
+func (proj *Proj) SetWorkers(n int) {
+	proj.workers = n
+}
+
+// SetCache set cache, unchanged files in it are parsed on demand
+func (proj *Proj) SetCache(c *cache.Cache) {
+	proj.cache = c
+}
+
+// ScanDir scan go files in dir
+func (proj *Proj) ScanDir(dir string, opts scan.Options) {
+	files := scanDir(dir, opts, proj.workers, proj.cache)
 	for _, f := range files {
 		proj.AddFile(f)
 	}
 }
 
+// Reload reload changed files in dir, removed or filtered out ones are dropped,
+// unsaved changes of other files are discarded. returns packages of changed files, sorted
+func (proj *Proj) Reload(dir string, opts scan.Options, changed []string) []string {
+	scanned := map[string]bool{}
+	paths, _ := scan.Files(dir, opts)
+	for _, path := range paths {
+		scanned[filepath.Clean(path)] = true
+	}
+	reload := map[string]bool{} // key: path of file, value: changed
+	for _, path := range changed {
+		reload[filepath.Clean(path)] = true
+	}
+
+	pkgs := map[string]bool{}
+	touched := map[*pkg.Pkg]bool{}
+	for _, f := range proj.Files() {
+		path := filepath.Clean(f.Path())
+		if !reload[path] && !f.Modified() {
+			continue
+		}
+		if reload[path] {
+			pkgs[f.PkgPath()] = true
+		} else {
+			reload[path] = false
+		}
+		p := proj.pkgs[f.PkgPath()]
+		p.RemoveFile(f.Path())
+		if len(p.Files()) == 0 {
+			delete(proj.pkgs, p.Path)
+		}
+	}
+
+	for path, isChanged := range reload {
+		if !scanned[path] {
+			continue
+		}
+		f, err := loadFile(path, proj.cache)
+		if err != nil {
+			continue
+		}
+		proj.AddFile(f)
+		if isChanged {
+			pkgs[f.PkgPath()] = true
+		}
+		touched[proj.pkgs[f.PkgPath()]] = true
+	}
+	for p := range touched {
+		p.SortFiles()
+	}
+
+	var names []string
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (proj *Proj) AddFile(files ...*file.File) {
 	proj.mtx.Lock()
 	defer proj.mtx.Unlock()
 
 	for _, f := range files {
-		p, ok := proj.pkgs[f.Pkg()]
+		if path, ok := proj.importPath(f); ok {
+			f.SetPkgPath(path)
+		}
+		p, ok := proj.pkgs[f.PkgPath()]
 		if !ok {
-			p = pkg.New(f.Pkg())
-			proj.pkgs[f.Pkg()] = p
+			p = pkg.New(f.PkgPath(), f.Pkg())
+			proj.pkgs[f.PkgPath()] = p
 		}
 		p.AddFile(f)
 	}
 }
 
-func (proj *Proj) GetFunc(pkg, name string) map[*file.File]*dst.FuncDecl {
-	p, ok := proj.pkgs[pkg]
+// importPath import path of file, computed from go.mod
+func (proj *Proj) importPath(f *file.File) (string, bool) {
+	if filepath.Ext(f.Path()) != ".go" {
+		return "", false
+	}
+	dir, err := filepath.Abs(filepath.Dir(f.Path()))
+	if err != nil {
+		return "", false
+	}
+	mod, ok := proj.module(dir)
+	if !ok {
+		return "", false
+	}
+	rel, err := filepath.Rel(mod.root, dir)
+	if err != nil {
+		return "", false
+	}
+	path := mod.path
+	if rel != "." {
+		path = fmt.Sprintf("%s/%s", path, filepath.ToSlash(rel))
+	}
+	// external test package in same dir
+	if strings.HasSuffix(f.Pkg(), "_test") {
+		path += "_test"
+	}
+	return path, true
+}
+
+// module search go.mod from dir to root
+func (proj *Proj) module(dir string) (module, bool) {
+	if mod, ok := proj.modules[dir]; ok {
+		return mod, len(mod.root) > 0
+	}
+
+	var mod module
+	if bs, err := ioutil.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		mod = module{root: dir, path: modulePath(string(bs))}
+	} else if parent := filepath.Dir(dir); parent != dir {
+		mod, _ = proj.module(parent)
+	}
+	proj.modules[dir] = mod
+	return mod, len(mod.root) > 0
+}
+
+// modulePath module path in go.mod
+func modulePath(mod string) string {
+	for _, line := range strings.Split(mod, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], "\"`")
+		}
+	}
+	return ""
+}
+
+// pkgOf package by key, or by package name if no package with the key
+func (proj *Proj) pkgOf(key string) (*pkg.Pkg, bool) {
+	key = strings.Trim(key, "*")
+	if p, ok := proj.pkgs[key]; ok {
+		return p, true
+	}
+
+	var found *pkg.Pkg
+	for _, p := range proj.pkgs {
+		if p.Name != key {
+			continue
+		}
+		if found == nil || p.Path < found.Path {
+			found = p
+		}
+	}
+	return found, found != nil
+}
+
+// lookup package of qualifier used in file f of curPkg by import table of f,
+// of any file in curPkg if f is nil. by package name only if no file imports it.
+// e.g. book of book.Book
+func (proj *Proj) lookup(f *file.File, curPkg, qualifier string) (*pkg.Pkg, bool) {
+	qualifier = strings.Trim(qualifier, "*")
+	if f != nil {
+		if path, ok := f.ImportPath(qualifier, proj.pkgName); ok {
+			p, ok := proj.pkgs[path]
+			return p, ok
+		}
+	}
+	if cur, ok := proj.pkgOf(curPkg); ok {
+		if path, ok := cur.ImportPath(qualifier, proj.pkgName); ok {
+			// imported by other files only, not a package in f
+			if f != nil {
+				return nil, false
+			}
+			p, ok := proj.pkgs[path]
+			return p, ok
+		}
+	}
+	return proj.pkgOf(qualifier)
+}
+
+// pkgName package name of import path
+func (proj *Proj) pkgName(path string) string {
+	if p, ok := proj.pkgs[path]; ok {
+		return p.Name
+	}
+	arr := strings.Split(path, "/")
+	name := arr[len(arr)-1]
+	// e.g. github.com/go-redis/redis/v8
+	if len(arr) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = arr[len(arr)-2]
+	}
+	// e.g. gopkg.in/yaml.v2
+	if idx := strings.Index(name, ".v"); idx > 0 {
+		name = name[:idx]
+	}
+	return name
+}
+
+// Resolve package and name of type used in curPkg.
+// e.g. *book.Book -> package of book, Book
+func (proj *Proj) Resolve(curPkg, typ string) (*pkg.Pkg, string, bool) {
+	return proj.resolve(nil, curPkg, typ)
+}
+
+// ResolveIn package and name of type used in file, by import table of the file
+func (proj *Proj) ResolveIn(f *file.File, typ string) (*pkg.Pkg, string, bool) {
+	return proj.resolve(f, f.PkgPath(), typ)
+}
+
+func (proj *Proj) resolve(f *file.File, curPkg, typ string) (*pkg.Pkg, string, bool) {
+	typ = strings.Trim(typ, "*")
+	if arr := strings.Split(typ, "."); len(arr) == 2 {
+		p, ok := proj.lookup(f, curPkg, arr[0])
+		return p, arr[1], ok
+	}
+	p, ok := proj.pkgOf(curPkg)
+	return p, typ, ok
+}
+
+// SetTypes use type info of go/types for exact analysis
+func (proj *Proj) SetTypes(info *typed.Info) {
+	proj.types = info
+}
+
+// HasTypes type info is loaded
+func (proj *Proj) HasTypes() bool {
+	return proj.types != nil
+}
+
+// TypeOf exact type of expr in file, qualified by package name used in file.
+// false if type info not loaded or type is interface
+func (proj *Proj) TypeOf(f *file.File, expr dst.Expr) (string, bool) {
+	if proj.types == nil {
+		return "", false
+	}
+	start, end, ok := f.Span(expr)
+	if !ok {
+		return "", false
+	}
+	t, ok := proj.types.TypeOf(f.Path(), start, end)
+	if !ok || types.IsInterface(t) {
+		return "", false
+	}
+	return proj.types.TypeString(f.Path(), t), true
+}
+
+// FuncOf function or method referenced by expr in file. e.g. handle, api.Handle, ctrl.List
+func (proj *Proj) FuncOf(f *file.File, expr dst.Expr) (map[*file.File]*dst.FuncDecl, bool) {
+	if proj.types == nil {
+		return nil, false
+	}
+	if sel, ok := expr.(*dst.SelectorExpr); ok {
+		expr = sel.Sel
+	}
+	start, _, ok := f.Span(expr)
+	if !ok {
+		return nil, false
+	}
+	obj, ok := proj.types.ObjectOf(f.Path(), start)
+	if !ok {
+		return nil, false
+	}
+	fn, ok := obj.(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return nil, false
+	}
+	p, ok := proj.pkgs[fn.Pkg().Path()]
+	if !ok {
+		return nil, false
+	}
+
+	var recv string
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		rt := sig.Recv().Type()
+		if ptr, ok := rt.(*types.Pointer); ok {
+			rt = ptr.Elem()
+		}
+		named, ok := rt.(*types.Named)
+		if !ok {
+			return nil, false
+		}
+		recv = named.Obj().Name()
+	}
+	ffs := p.GetMethod(fn.Name(), recv)
+	return ffs, len(ffs) > 0
+}
+
+// GetFunc search function in curPkg, name maybe with package. e.g. handle, api.Register
+func (proj *Proj) GetFunc(curPkg, name string) map[*file.File]*dst.FuncDecl {
+	p, name, ok := proj.Resolve(curPkg, name)
+	if !ok {
+		return nil
+	}
+	return p.GetFunc(name)
+}
+
+// GetFuncIn search function used in file, name maybe with package imported by the file
+func (proj *Proj) GetFuncIn(f *file.File, name string) map[*file.File]*dst.FuncDecl {
+	p, name, ok := proj.ResolveIn(f, name)
 	if !ok {
 		return nil
 	}
 	return p.GetFunc(name)
 }
 
+// GetMethod method of type used in curPkg. e.g. *controller.User, List
+func (proj *Proj) GetMethod(curPkg, typ, name string) map[*file.File]*dst.FuncDecl {
+	p, recv, ok := proj.Resolve(curPkg, typeName(typ))
+	if !ok || len(recv) == 0 {
+		return nil
+	}
+	return p.GetMethod(name, recv)
+}
+
+// FieldType type of field of struct used in curPkg, and package the field type is used in.
+// e.g. *api.Handlers, User -> import path of api, *UserCtrl
+func (proj *Proj) FieldType(curPkg, typ, field string) (string, string, bool) {
+	p, name, ok := proj.Resolve(curPkg, typeName(typ))
+	if !ok {
+		return "", "", false
+	}
+	ft, ok := p.GetStructFieldType(name, field)
+	return p.Path, ft, ok
+}
+
+// CallResult result types of call in curPkg. e.g. controller.NewUser(svc) -> *controller.User
+func (proj *Proj) CallResult(curPkg string, call *dst.CallExpr, vars map[string]string) []string {
+	return proj.getVarFromCallExprResult(curPkg, call, vars)
+}
+
+// typeName named type of value. e.g. &api.User{Svc: svc} -> api.User
+func typeName(typ string) string {
+	if i := strings.IndexAny(typ, "{("); i >= 0 {
+		typ = typ[:i]
+	}
+	return strings.TrimLeft(typ, "&*")
+}
+
+// GetMainFunc main function of main packages
+func (proj *Proj) GetMainFunc() map[*file.File]*dst.FuncDecl {
+	ffs := make(map[*file.File]*dst.FuncDecl)
+	for _, p := range proj.pkgs {
+		if p.Name != "main" {
+			continue
+		}
+		for f, fd := range p.GetFunc("main") {
+			ffs[f] = fd
+		}
+	}
+	return ffs
+}
+
 func (proj *Proj) GetGlobalVar(pkg string) map[string]string {
-	p, ok := proj.pkgs[pkg]
+	p, ok := proj.pkgOf(pkg)
 	if !ok {
 		return nil
 	}
 	return p.GetGlobalVar()
 }
 
+// GetGlobalValue value of global var used in curPkg, name maybe with package. e.g. routes, api.Routes
+func (proj *Proj) GetGlobalValue(curPkg, name string) (dst.Expr, bool) {
+	p, name, ok := proj.Resolve(curPkg, name)
+	if !ok {
+		return nil, false
+	}
+	return p.GetGlobalValue(name)
+}
+
+// Declares whether name is declared at package level of pkg
+func (proj *Proj) Declares(pkg, name string) bool {
+	p, ok := proj.pkgOf(pkg)
+	return ok && p.Declares(name)
+}
+
+// GetPkgWithImported packages importing path, sorted by import path
 func (proj *Proj) GetPkgWithImported(path string) (pkgs []*pkg.Pkg) {
-	for _, p := range proj.pkgs {
+	var names []string
+	for name, p := range proj.pkgs {
 		if _, err := p.GetImported(path); err == nil {
-			pkgs = append(pkgs, p)
+			names = append(names, name)
 		}
 	}
+	sort.Strings(names)
+	for _, name := range names {
+		pkgs = append(pkgs, proj.pkgs[name])
+	}
 	return
 }
 
-func (proj *Proj) GetStruct(pkg, name string) (*dst.StructType, error) {
-	arr := strings.Split(name, ".")
-	if len(arr) == 2 {
-		pkg = arr[0]
+// GetStruct search struct used in curPkg, name maybe with package. e.g. book.Book
+func (proj *Proj) GetStruct(curPkg, name string) (*dst.StructType, error) {
+	p, name, ok := proj.Resolve(curPkg, name)
+	if !ok {
+		return nil, common.ErrNotFind
 	}
+	return p.GetStruct(name)
+}
 
-	p, ok := proj.pkgs[pkg]
+// GetStructIn search struct used in file, name maybe with package imported by the file
+func (proj *Proj) GetStructIn(f *file.File, name string) (*dst.StructType, error) {
+	p, name, ok := proj.ResolveIn(f, name)
 	if !ok {
 		return nil, common.ErrNotFind
 	}
-
 	return p.GetStruct(name)
 }
 
+// GetType search named type used in curPkg, return underlying type
+func (proj *Proj) GetType(curPkg, name string) (string, error) {
+	p, name, ok := proj.Resolve(curPkg, name)
+	if !ok {
+		return "", common.ErrNotFind
+	}
+	return p.GetType(name)
+}
+
 func (proj *Proj) GetVarsFromStmt(stmt interface{}, curPkg string, outVars map[string]string) map[string]string {
 	selFn := func(sel *dst.SelectorExpr, v string) {
 		selX, ok := sel.X.(*dst.Ident)
@@ -198,10 +584,12 @@ func (proj *Proj) GetVarsFromStmt(stmt interface{}, curPkg string, outVars map[s
 	return vars
 }
 
-func (proj *Proj) isPkg(name string) bool {
-	name = strings.Trim(name, "*")
-	_, ok := proj.pkgs[name]
-	return ok
+func (proj *Proj) isPkg(curPkg, name string) (string, bool) {
+	p, ok := proj.lookup(nil, curPkg, name)
+	if !ok {
+		return "", false
+	}
+	return p.Path, true
 }
 
 func (proj *Proj) isFunc(name string) bool {
@@ -224,7 +612,7 @@ func (proj *Proj) isStruct(name string) bool {
 }
 
 func (proj *Proj) getFuncResult(pkgName, FnName, recvName string) []string {
-	p, ok := proj.pkgs[pkgName]
+	p, ok := proj.pkgOf(pkgName)
 	if !ok {
 		return nil
 	}
@@ -232,7 +620,7 @@ func (proj *Proj) getFuncResult(pkgName, FnName, recvName string) []string {
 }
 
 func (proj *Proj) getStructFieldType(pkgName, structName, fieldName string) (string, bool) {
-	p, ok := proj.pkgs[pkgName]
+	p, ok := proj.pkgOf(pkgName)
 	if !ok {
 		return "", false
 	}
@@ -243,8 +631,8 @@ func (proj *Proj) getVarFromCallExprResult(curPkg string, stmt interface{}, outV
 	fn := func(args ...string) []string {
 		var pkgName, struName, funcName, fieldName string
 		for _, arg := range args {
-			if proj.isPkg(arg) {
-				pkgName = strings.Trim(arg, "*")
+			if path, ok := proj.isPkg(curPkg, arg); ok {
+				pkgName = path
 				continue
 			}
 			if proj.isStruct(arg) {
@@ -272,6 +660,14 @@ func (proj *Proj) getVarFromCallExprResult(curPkg string, stmt interface{}, outV
 	switch stmt.(type) {
 	case *dst.SelectorExpr:
 		sel := stmt.(*dst.SelectorExpr)
+		// function of package. e.g. controller.NewUser
+		if x, ok := sel.X.(*dst.Ident); ok {
+			if _, isVar := outVars[x.Name]; !isVar {
+				if path, ok := proj.isPkg(curPkg, x.Name); ok && path != curPkg {
+					return proj.getFuncResult(path, sel.Sel.Name, "")
+				}
+			}
+		}
 		rs := proj.getVarFromCallExprResult(curPkg, sel.X, outVars)
 		if len(rs) != 1 {
 			return nil
@@ -338,16 +734,18 @@ func (proj *Proj) interfaceOfCompositeLit(curPkg string, stmt interface{}, outVa
 	return value
 }
 
-func (proj *Proj) interfaceOfStmt(pkg string, stmt interface{}) map[string]string {
-	name := common.ToStr(stmt)
-	pkg, name = slitDot(pkg, name)
+func (proj *Proj) interfaceOfStmt(curPkg string, stmt interface{}) map[string]string {
+	p, name, ok := proj.Resolve(curPkg, common.ToStr(stmt))
+	if !ok {
+		return nil
+	}
 
-	fn, err := proj.GetStruct(pkg, name)
+	fn, err := p.GetStruct(name)
 	if err != nil {
 		return nil
 	}
 
-	return proj.getInterfaceOfStruct(pkg, fn)
+	return proj.getInterfaceOfStruct(p.Path, fn)
 }
 
 func (proj *Proj) getInterfaceOfStruct(curPkg string, stru *dst.StructType) map[string]string {
@@ -402,32 +800,141 @@ func (proj *Proj) Save() error {
 	return nil
 }
 
-// scanDir scan go file and parse to ast
-func scanDir(dir string) (asts []*file.File) {
-	if strings.Contains(dir, "vendor") {
-		return
+// Diff write unified diff of dirty files, sorted by package
+func (proj *Proj) Diff(w io.Writer) error {
+	var names []string
+	for name := range proj.pkgs {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := proj.pkgs[name].Diff(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	fs, err := ioutil.ReadDir(dir)
+// scanDir scan go files and parse to ast by workers, in order of paths.
+// files unchanged since cached are loaded from index
+func scanDir(dir string, opts scan.Options, workers int, c *cache.Cache) (asts []*file.File) {
+	paths, err := scan.Files(dir, opts)
 	if err != nil {
 		return
 	}
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	parsed := make([]*file.File, len(paths))
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				if f, err := loadFile(paths[i], c); err == nil {
+					parsed[i] = f
+				}
+			}
+		}()
+	}
+	for i := range paths {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
 
-	for _, f := range fs {
-		path := filepath.Join(dir, f.Name())
-		if f.IsDir() {
-			asts = append(asts, scanDir(path)...)
+	for _, f := range parsed {
+		if f != nil {
+			asts = append(asts, f)
 		}
-		if !strings.HasSuffix(path, ".go") {
-			continue
+	}
+	return
+}
+
+func loadFile(path string, c *cache.Cache) (*file.File, error) {
+	if c == nil {
+		return file.New(path)
+	}
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	idx, _ := c.File(path, file.Hash(bs))
+	return file.Load(path, bs, idx)
+}
+
+// Files all files, sorted by path
+func (proj *Proj) Files() []*file.File {
+	var files []*file.File
+	for _, p := range proj.pkgs {
+		files = append(files, p.Files()...)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path() < files[j].Path()
+	})
+	return files
+}
+
+// Digest hash of files in package and packages it imports in project,
+// changes if any file the package depends on changes
+func (proj *Proj) Digest(pkgPath string) string {
+	var hashes []string
+	for p := range proj.deps(pkgPath) {
+		for _, f := range p.Files() {
+			hashes = append(hashes, f.Path()+":"+f.Hash())
 		}
-		g, err := file.New(path)
-		if err != nil {
-			continue
+	}
+	sort.Strings(hashes)
+	return file.Hash([]byte(strings.Join(hashes, "\n")))
+}
+
+// DependsOn whether package or packages it imports is any of pkgs,
+// removed ones included
+func (proj *Proj) DependsOn(pkgPath string, pkgs map[string]bool) bool {
+	if pkgs[pkgPath] {
+		return true
+	}
+	for p := range proj.deps(pkgPath) {
+		if pkgs[p.Path] {
+			return true
+		}
+		for _, f := range p.Files() {
+			for _, path := range f.Imports() {
+				if pkgs[path] {
+					return true
+				}
+			}
 		}
-		asts = append(asts, g)
 	}
-	return
+	return false
+}
+
+// deps package and packages it imports in project
+func (proj *Proj) deps(pkgPath string) map[*pkg.Pkg]bool {
+	seen := map[*pkg.Pkg]bool{}
+	var walk func(p *pkg.Pkg)
+	walk = func(p *pkg.Pkg) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		for _, f := range p.Files() {
+			for _, path := range f.Imports() {
+				if dep, ok := proj.pkgOf(path); ok {
+					walk(dep)
+				} else if dep, ok := proj.pkgOf(proj.pkgName(path)); ok {
+					walk(dep)
+				}
+			}
+		}
+	}
+	if p, ok := proj.pkgOf(pkgPath); ok {
+		walk(p)
+	}
+	return seen
 }
 
 func slitDot(curPkg, str string) (string, string) {
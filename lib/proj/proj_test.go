@@ -2,12 +2,16 @@ package proj
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
+
+	"github.com/hocv/gin-swagger-gen/lib/file"
+	"github.com/hocv/gin-swagger-gen/lib/scan"
 )
 
 func TestFindStructInterface(t *testing.T) {
 	a := New()
-	a.ScanDir("./test")
+	a.ScanDir("./test", scan.Options{})
 	stru, err := a.GetStruct("test", "Resp3")
 	if err != nil {
 		t.Fatal(err)
@@ -16,3 +20,74 @@ func TestFindStructInterface(t *testing.T) {
 	v := a.getInterfaceOfStruct("test", stru)
 	fmt.Println(v)
 }
+
+func TestGetStructByImportPath(t *testing.T) {
+	a := New()
+	a.ScanDir("./test", scan.Options{})
+	stru, err := a.GetStruct("test", "model.User")
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if name := stru.Fields.List[0].Names[0].Name; name != "Nickname" {
+		t.Fatalf("should be v2 model, but field is %s", name)
+	}
+	if _, err := a.GetStruct("github.com/hocv/gin-swagger-gen/lib/proj/test/v1/model", "User"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanDirWorkers(t *testing.T) {
+	files := func(workers int) map[string][]string {
+		a := New()
+		a.SetWorkers(workers)
+		a.ScanDir("./test", scan.Options{})
+		res := map[string][]string{}
+		for path, p := range a.pkgs {
+			for _, f := range p.Files() {
+				res[path] = append(res[path], f.Path())
+			}
+		}
+		return res
+	}
+
+	want := files(1)
+	if len(want) == 0 {
+		t.Fatal("no package scanned")
+	}
+	for i := 0; i < 5; i++ {
+		if got := files(8); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%v, want %v", got, want)
+		}
+	}
+}
+
+func TestResolveInFile(t *testing.T) {
+	a := New()
+	add := func(path, src string) *file.File {
+		f, err := file.New(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.SetPkgPath(path)
+		a.AddFile(f)
+		return f
+	}
+	add("example.com/v1/model", "package model\n\ntype User struct{ ID int }\n")
+	add("example.com/v2/model", "package model\n\ntype User struct{ Name string }\n")
+	// same alias for different packages in files of one package
+	f1 := add("example.com/api", "package api\n\nimport m \"example.com/v1/model\"\n\nvar u1 m.User\n")
+	f2 := add("example.com/api", "package api\n\nimport m \"example.com/v2/model\"\n\nvar u2 m.User\n")
+	add("example.com/api", "package api\n\nimport x \"example.com/v1/model\"\n\nvar u3 x.User\n")
+
+	for f, want := range map[*file.File]string{f1: "example.com/v1/model", f2: "example.com/v2/model"} {
+		p, name, ok := a.ResolveIn(f, "*m.User")
+		if !ok || p.Path != want || name != "User" {
+			t.Fatalf("%v %s, want %s", p, name, want)
+		}
+	}
+	// imported by other file only
+	if p, _, ok := a.ResolveIn(f1, "x.User"); ok {
+		t.Fatalf("x is not imported by file, but %s", p.Path)
+	}
+}
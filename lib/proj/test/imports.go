@@ -0,0 +1,7 @@
+package test
+
+import (
+	"github.com/hocv/gin-swagger-gen/lib/proj/test/v2/model"
+)
+
+var user = model.User{}
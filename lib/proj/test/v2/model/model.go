@@ -0,0 +1,5 @@
+package model
+
+type User struct {
+	Nickname string `json:"nickname"`
+}
@@ -0,0 +1,81 @@
+package scan
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule a line of .gitignore
+type ignoreRule struct {
+	pattern string
+	negate  bool // !pattern
+	dirOnly bool // pattern/
+}
+
+// gitignore rules of .gitignore in dir
+type gitignore struct {
+	base  string // slash separated dir relative to root, "." for root
+	rules []ignoreRule
+}
+
+func loadGitignore(dir, base string) (*gitignore, bool) {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	gi := &gitignore{base: base}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var r ignoreRule
+		if strings.HasPrefix(line, "!") {
+			r.negate, line = true, line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly, line = true, strings.TrimRight(line, "/")
+		}
+		// pattern without slash matches name at any level
+		if !strings.Contains(line, "/") {
+			line = "**/" + line
+		}
+		r.pattern = strings.TrimPrefix(line, "/")
+		gi.rules = append(gi.rules, r)
+	}
+	return gi, len(gi.rules) > 0
+}
+
+// match result of the last matched rule, false if none matches
+func (gi *gitignore) match(rel string, isDir bool) (ignore, matched bool) {
+	if gi.base != "." {
+		if !strings.HasPrefix(rel, gi.base+"/") {
+			return false, false
+		}
+		rel = rel[len(gi.base)+1:]
+	}
+	for _, r := range gi.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if Match(r.pattern, rel) {
+			ignore, matched = !r.negate, true
+		}
+	}
+	return
+}
+
+// ignored path is ignored by .gitignore files, the deeper one has priority
+func ignored(ignores []*gitignore, rel string, isDir bool) bool {
+	for i := len(ignores) - 1; i >= 0; i-- {
+		if ignore, ok := ignores[i].match(rel, isDir); ok {
+			return ignore
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package scan
+
+import (
+	"go/build"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Options options of scanning
+type Options struct {
+	Include []string // glob patterns of files or dirs to scan, relative to root, all if empty. e.g. api/**
+	Exclude []string // glob patterns of files or dirs not to scan, relative to root. e.g. **/mock
+	Tests   bool     // scan _test.go files
+	Tags    []string // build tags, GOOS and GOARCH of current platform are used
+}
+
+// Files go files in root to be parsed, dirs are walked in order of name
+func Files(root string, opts Options) ([]string, error) {
+	s, err := walk(root, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.files, nil
+}
+
+// Dirs dirs in root walked for go files, root included
+func Dirs(root string, opts Options) ([]string, error) {
+	s, err := walk(root, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.dirs, nil
+}
+
+func walk(root string, opts Options) (*scanner, error) {
+	ctxt := build.Default
+	ctxt.BuildTags = opts.Tags
+	s := &scanner{
+		root: root,
+		opts: opts,
+		ctxt: &ctxt,
+	}
+	return s, s.walk(root, nil)
+}
+
+type scanner struct {
+	root  string
+	opts  Options
+	ctxt  *build.Context
+	files []string
+	dirs  []string
+}
+
+func (s *scanner) walk(dir string, ignores []*gitignore) error {
+	s.dirs = append(s.dirs, dir)
+	fs, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	if gi, ok := loadGitignore(dir, s.rel(dir)); ok {
+		ignores = append(ignores, gi)
+	}
+
+	for _, f := range fs {
+		name := f.Name()
+		p := filepath.Join(dir, name)
+		rel := s.rel(p)
+		if ignored(ignores, rel, f.IsDir()) || matchAny(s.opts.Exclude, rel) {
+			continue
+		}
+
+		if f.IsDir() {
+			if skipDir(name) {
+				continue
+			}
+			if err := s.walk(p, ignores); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if !s.opts.Tests && strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if len(s.opts.Include) > 0 && !matchAny(s.opts.Include, rel) {
+			continue
+		}
+		// build constraints in file name and //go:build
+		if ok, err := s.ctxt.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+		s.files = append(s.files, p)
+	}
+	return nil
+}
+
+// rel slash separated path relative to root
+func (s *scanner) rel(p string) string {
+	rel, err := filepath.Rel(s.root, p)
+	if err != nil {
+		return filepath.ToSlash(p)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// skipDir dirs ignored by go tool
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" ||
+		strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// matchAny path or one of its parent dirs matches any of patterns
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+		for p := rel; p != "." && p != "/" && len(p) > 0; p = path.Dir(p) {
+			if Match(pattern, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Match slash separated path matches glob pattern, ** matches any number of dirs.
+// e.g. api/**/*.go matches api/v1/user.go
+func Match(pattern, p string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+func matchSegments(pattern, segs []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segs); i++ {
+				if matchSegments(pattern[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], segs[0]); err != nil || !ok {
+			return false
+		}
+		pattern, segs = pattern[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
@@ -0,0 +1,94 @@
+package scan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	arr := []struct {
+		Pattern string
+		Path    string
+		Match   bool
+	}{
+		{Pattern: "api/**/*.go", Path: "api/v1/user.go", Match: true},
+		{Pattern: "api/**/*.go", Path: "api/user.go", Match: true},
+		{Pattern: "**/mock", Path: "internal/mock", Match: true},
+		{Pattern: "**/mock", Path: "mock", Match: true},
+		{Pattern: "api/*.go", Path: "api/v1/user.go", Match: false},
+		{Pattern: "internal", Path: "api/internal", Match: false},
+	}
+	for _, m := range arr {
+		if ok := Match(m.Pattern, m.Path); ok != m.Match {
+			t.Fatalf("%s %s -> %t, but: %t", m.Pattern, m.Path, m.Match, ok)
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	root, err := ioutil.TempDir("", "scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		"main.go":                 "package main\n",
+		"main_test.go":            "package main\n",
+		"gen.go":                  "// Code generated by mockgen. DO NOT EDIT.\n\npackage main\n",
+		"ignore.go":               "//go:build ignore\n\npackage main\n",
+		"custom.go":               "//go:build custom\n\npackage main\n",
+		"api/user.go":             "package api\n",
+		"api/user.pb.go":          "package api\n",
+		"api/keep.pb.go":          "package api\n",
+		"api/mock/mock.go":        "package mock\n",
+		"tmp/tmp.go":              "package tmp\n",
+		"vendor/lib/lib.go":       "package lib\n",
+		"vendors/lib.go":          "package vendors\n",
+		".gitignore":              "tmp/\n*.pb.go\n",
+		"api/.gitignore":          "!keep.pb.go\n",
+		"testdata/fixture/fix.go": "package fixture\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rel := func(opts Options) (res []string) {
+		fs, err := Files(root, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range fs {
+			r, _ := filepath.Rel(root, f)
+			res = append(res, filepath.ToSlash(r))
+		}
+		return
+	}
+
+	got := rel(Options{Exclude: []string{"**/mock"}})
+	want := []string{"api/keep.pb.go", "api/user.go", "gen.go", "main.go", "vendors/lib.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%v, want %v", got, want)
+	}
+
+	got = rel(Options{Include: []string{"api"}, Tests: true, Tags: []string{"custom"}})
+	want = []string{"api/keep.pb.go", "api/mock/mock.go", "api/user.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%v, want %v", got, want)
+	}
+
+	got = rel(Options{Include: []string{"*.go"}, Tests: true, Tags: []string{"custom"}})
+	want = []string{"custom.go", "gen.go", "main.go", "main_test.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%v, want %v", got, want)
+	}
+}
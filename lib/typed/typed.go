@@ -0,0 +1,176 @@
+package typed
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const loadMode = packages.NeedName |
+	packages.NeedFiles |
+	packages.NeedSyntax |
+	packages.NeedImports |
+	packages.NeedDeps |
+	packages.NeedTypes |
+	packages.NeedTypesInfo |
+	packages.NeedTypesSizes
+
+// span offset range of expr in file
+type span struct {
+	start int
+	end   int
+}
+
+type fileInfo struct {
+	pkg     *types.Package
+	types   map[span]types.Type
+	objects map[int]types.Object // key: offset of ident
+	imports map[string]string    // key: import path, value: name used in file
+}
+
+// Info type info of packages, indexed by file and offset
+type Info struct {
+	files map[string]*fileInfo // key: absolute file name
+}
+
+// Load load and type check packages in dir
+func Load(dir string) (*Info, error) {
+	cfg := &packages.Config{
+		Mode: loadMode,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{files: map[string]*fileInfo{}}
+	for _, p := range pkgs {
+		info.add(p)
+	}
+	return info, nil
+}
+
+func (info *Info) add(p *packages.Package) {
+	if p.Types == nil || p.TypesInfo == nil {
+		return
+	}
+
+	fileOf := func(pos token.Pos) (*fileInfo, int, bool) {
+		tf := p.Fset.File(pos)
+		if tf == nil {
+			return nil, 0, false
+		}
+		fi, ok := info.files[tf.Name()]
+		if !ok {
+			return nil, 0, false
+		}
+		return fi, tf.Offset(pos), true
+	}
+
+	for _, af := range p.Syntax {
+		tf := p.Fset.File(af.Pos())
+		if tf == nil {
+			continue
+		}
+		info.files[tf.Name()] = newFileInfo(p, af)
+	}
+
+	for expr, tv := range p.TypesInfo.Types {
+		fi, start, ok := fileOf(expr.Pos())
+		if !ok {
+			continue
+		}
+		fi.types[span{start: start, end: start + int(expr.End()-expr.Pos())}] = tv.Type
+	}
+
+	for _, objs := range []map[*ast.Ident]types.Object{p.TypesInfo.Defs, p.TypesInfo.Uses} {
+		for ident, obj := range objs {
+			if obj == nil {
+				continue
+			}
+			fi, start, ok := fileOf(ident.Pos())
+			if !ok {
+				continue
+			}
+			fi.objects[start] = obj
+		}
+	}
+}
+
+func newFileInfo(p *packages.Package, af *ast.File) *fileInfo {
+	fi := &fileInfo{
+		pkg:     p.Types,
+		types:   map[span]types.Type{},
+		objects: map[int]types.Object{},
+		imports: map[string]string{},
+	}
+	names := map[string]string{}
+	for _, ip := range p.Types.Imports() {
+		names[ip.Path()] = ip.Name()
+	}
+	for _, spec := range af.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			fi.imports[path] = spec.Name.Name
+			continue
+		}
+		if name, ok := names[path]; ok {
+			fi.imports[path] = name
+		}
+	}
+	return fi
+}
+
+func (info *Info) file(filename string) (*fileInfo, bool) {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	fi, ok := info.files[filename]
+	return fi, ok
+}
+
+// TypeOf type of expr in file, start and end are offsets of expr
+func (info *Info) TypeOf(filename string, start, end int) (types.Type, bool) {
+	fi, ok := info.file(filename)
+	if !ok {
+		return nil, false
+	}
+	t, ok := fi.types[span{start: start, end: end}]
+	return t, ok
+}
+
+// ObjectOf object defined or used by ident at offset in file
+func (info *Info) ObjectOf(filename string, offset int) (types.Object, bool) {
+	fi, ok := info.file(filename)
+	if !ok {
+		return nil, false
+	}
+	obj, ok := fi.objects[offset]
+	return obj, ok
+}
+
+// TypeString type to string, qualified by package name used in file.
+// e.g. *book.Book, []Resp
+func (info *Info) TypeString(filename string, t types.Type) string {
+	fi, ok := info.file(filename)
+	return types.TypeString(t, func(p *types.Package) string {
+		if !ok {
+			return p.Name()
+		}
+		if p.Path() == fi.pkg.Path() {
+			return ""
+		}
+		if name, ok := fi.imports[p.Path()]; ok {
+			return name
+		}
+		return p.Name()
+	})
+}
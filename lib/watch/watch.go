@@ -0,0 +1,139 @@
+package watch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/hocv/gin-swagger-gen/lib/file"
+	"github.com/hocv/gin-swagger-gen/lib/scan"
+)
+
+// Watcher watch go files in dirs of root, changed files are sent in batch
+// after writes stop for a delay
+type Watcher struct {
+	root   string
+	opts   scan.Options
+	delay  time.Duration
+	fsw    *fsnotify.Watcher
+	dirs   map[string]bool                        // watched dirs
+	hashes map[string]string                      // key: path of file, value: hash of source seen last run
+	after  func(d time.Duration) <-chan time.Time // timer of delay, time.After
+}
+
+func New(root string, opts scan.Options, delay time.Duration) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		root:   root,
+		opts:   opts,
+		delay:  delay,
+		fsw:    fsw,
+		dirs:   map[string]bool{},
+		hashes: map[string]string{},
+		after:  time.After,
+	}
+	if err := w.addDirs(); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Seen set hashes of files seen last run, writes not changing them are ignored.
+// e.g. comments saved by generator
+func (w *Watcher) Seen(hashes map[string]string) {
+	w.hashes = hashes
+}
+
+// Run call fn with changed files after each burst of writes, until watcher is closed
+func (w *Watcher) Run(fn func(changed []string)) error {
+	return w.run(w.fsw.Events, w.fsw.Errors, fn)
+}
+
+// run debounce events, timer is restarted by each change
+func (w *Watcher) run(events <-chan fsnotify.Event, errs <-chan error, fn func(changed []string)) error {
+	pending := map[string]bool{}
+	var fire <-chan time.Time
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if w.changed(ev) {
+				pending[ev.Name] = true
+				fire = w.after(w.delay)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			return err
+		case <-fire:
+			fire = nil
+			var changed []string
+			for p := range pending {
+				changed = append(changed, p)
+			}
+			sort.Strings(changed)
+			pending = map[string]bool{}
+			fn(changed)
+		}
+	}
+}
+
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+// addDirs watch dirs walked by scan, new ones included
+func (w *Watcher) addDirs() error {
+	dirs, err := scan.Dirs(w.root, w.opts)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fsw.Add(dir); err != nil {
+			return err
+		}
+		w.dirs[dir] = true
+	}
+	return nil
+}
+
+// changed event changes a go file since last run
+func (w *Watcher) changed(ev fsnotify.Event) bool {
+	path := filepath.Clean(ev.Name)
+	// watch is removed with dir
+	if ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0 && w.dirs[path] {
+		delete(w.dirs, path)
+		return false
+	}
+	if ev.Op&fsnotify.Create != 0 {
+		if fi, err := os.Stat(ev.Name); err == nil && fi.IsDir() {
+			_ = w.addDirs()
+			return false
+		}
+	}
+	if !strings.HasSuffix(ev.Name, ".go") || ev.Op == fsnotify.Chmod {
+		return false
+	}
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		// removed or renamed
+		_, ok := w.hashes[path]
+		return ok
+	}
+	return w.hashes[path] != file.Hash(bs)
+}
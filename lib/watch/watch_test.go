@@ -0,0 +1,92 @@
+package watch
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/hocv/gin-swagger-gen/lib/file"
+	"github.com/hocv/gin-swagger-gen/lib/scan"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	src := []byte("package a\n")
+	a, b, c := filepath.Join(dir, "a.go"), filepath.Join(dir, "b.go"), filepath.Join(dir, "c.go")
+	for _, path := range []string{a, b} {
+		if err := ioutil.WriteFile(path, src, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := New(dir, scan.Options{}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	// c.go is seen last run and removed
+	w.Seen(map[string]string{a: file.Hash(src), b: file.Hash(src), c: file.Hash(src)})
+
+	// timers are fired by test
+	timers := make(chan chan time.Time, 10)
+	w.after = func(d time.Duration) <-chan time.Time {
+		if d != time.Second {
+			t.Errorf("delay %v", d)
+		}
+		ch := make(chan time.Time, 1)
+		timers <- ch
+		return ch
+	}
+	events := make(chan fsnotify.Event)
+	batches := make(chan []string, 10)
+	done := make(chan error)
+	go func() {
+		done <- w.run(events, nil, func(changed []string) {
+			batches <- changed
+		})
+	}()
+	// timer of change, none for ignored events
+	change := func(ev fsnotify.Event) chan time.Time {
+		events <- ev
+		select {
+		case timer := <-timers:
+			return timer
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%v: no timer", ev)
+			return nil
+		}
+	}
+
+	// same source, e.g. written by generator
+	events <- fsnotify.Event{Name: a, Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: a, Op: fsnotify.Chmod}
+	events <- fsnotify.Event{Name: filepath.Join(dir, "a.txt"), Op: fsnotify.Write}
+
+	// burst of writes, timer is restarted by each one
+	if err := ioutil.WriteFile(b, []byte("package a\n\nvar B = 1\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	first := change(fsnotify.Event{Name: b, Op: fsnotify.Write})
+	change(fsnotify.Event{Name: b, Op: fsnotify.Write})
+	first <- time.Now()
+	last := change(fsnotify.Event{Name: c, Op: fsnotify.Remove})
+	last <- time.Now()
+
+	select {
+	case changed := <-batches:
+		if len(changed) != 2 || changed[0] != b || changed[1] != c {
+			t.Fatalf("changed %v, want [%s %s]", changed, b, c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change")
+	}
+	close(events)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) > 0 {
+		t.Fatalf("burst should be debounced: %v", <-batches)
+	}
+}
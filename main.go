@@ -1,8 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 
+	"github.com/hocv/gin-swagger-gen/lib/cache"
+	"github.com/hocv/gin-swagger-gen/lib/conf"
+	"github.com/hocv/gin-swagger-gen/lib/watch"
 	"github.com/hocv/gin-swagger-gen/parser"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
@@ -11,17 +17,219 @@ var (
 	searchDir   = kingpin.Flag("dir", "Directory you want to pars").Short('d').Default("./").ExistingDir()
 	specifyFunc = kingpin.Flag("func.name", "specify the function to add comment").Short('f').String()
 	justPrint   = kingpin.Flag("just.print", "just print, no save to file").Short('p').Bool()
+	out         = kingpin.Flag("out", "write swagger document to file(.json, .yaml), no save to go file").Short('o').String()
+	specVersion = kingpin.Flag("spec.version", "version of document: 2.0, 3.0, 3.1").Default("2.0").Enum("2.0", "3.0", "3.1")
+	check       = kingpin.Flag("check", "check comments are up to date, no save to file, exit 1 if not").Bool()
+	showDiff    = kingpin.Flag("diff", "print unified diff of changes, no save to file").Bool()
+	useTypes    = kingpin.Flag("types", "use go/types for exact types, slower, project must compile").Bool()
+	useCache    = kingpin.Flag("cache", "cache parsed files and generated comments, only changed ones are analyzed next time").Bool()
+	cacheFile   = kingpin.Flag("cache.file", "cache file, "+cache.FileName+" in dir if not set").String()
+	workers     = kingpin.Flag("workers", "number of goroutines parsing files, NumCPU if 0").Short('j').Int()
+	configFile  = kingpin.Flag("config", "config file, .gin-swagger-gen.yaml(.yml, .toml) in dir if not set").Short('c').String()
+	info        = kingpin.Flag("info", "general api info, e.g. --info title=\"Book API\" --info host=localhost:8080").StringMap()
+	orphans     = kingpin.Flag("orphans", "functions with @Router not routed: report, or strip swag lines").Enum(conf.OrphansReport, conf.OrphansStrip)
+	securities  = kingpin.Flag("security", "security definition, e.g. --security \"apikey ApiKeyAuth in=header name=Authorization\"").Strings()
+
+	generateCmd = kingpin.Command("generate", "generate comments and documents").Default()
+	watchCmd    = kingpin.Command("watch", "watch dir, regenerate for changed packages on save")
+	debounce    = watchCmd.Flag("debounce", "wait for writes to stop before regenerating").Default("300ms").Duration()
 )
 
 func main() {
-	kingpin.Parse()
+	cmd := kingpin.Parse()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	switch cmd {
+	case watchCmd.FullCommand():
+		if err := watchDir(cfg); err != nil {
+			log.Fatalln(err)
+		}
+	case generateCmd.FullCommand():
+		generate(cfg, newParser(cfg, *useCache || len(*cacheFile) > 0))
+	}
+}
 
-	p := parser.New(*specifyFunc)
+// newParser parser of files in dir
+func newParser(cfg *conf.Config, withCache bool) *parser.Parser {
+	p, err := parser.New(cfg)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if withCache {
+		path := *cacheFile
+		if len(path) == 0 {
+			path = filepath.Join(*searchDir, cache.FileName)
+		}
+		p.UseCache(path)
+	}
 	p.ScanDir(*searchDir)
+	if *useTypes {
+		if err := p.LoadTypes(*searchDir); err != nil {
+			log.Println(err)
+		}
+	}
+	return p
+}
+
+func generate(cfg *conf.Config, p *parser.Parser) {
+	if *check {
+		p.Parse(false)
+		reportWarnings(p)
+		saveCache(p)
+		stale := p.Stale()
+		for _, s := range stale {
+			fmt.Println(s)
+		}
+		if reportOrphans(cfg, p) > 0 || len(stale) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *showDiff {
+		p.Parse(false)
+		reportWarnings(p)
+		if err := p.Diff(os.Stdout); err != nil {
+			log.Println(err)
+		}
+		saveCache(p)
+		return
+	}
+
 	p.Parse(*justPrint)
+	reportWarnings(p)
+	reportOrphans(cfg, p)
+	if len(*out) > 0 {
+		if err := p.WriteSpec(*out, *specVersion); err != nil {
+			log.Println(err)
+		}
+		saveCache(p)
+		return
+	}
 	if !*justPrint {
 		if err := p.Save(); err != nil {
 			log.Println(err)
 		}
+		if err := p.WriteOutputs(); err != nil {
+			log.Println(err)
+		}
+	}
+	saveCache(p)
+}
+
+// watchDir regenerate after go files in dir change, project is scanned once
+// and only changed files are reloaded, handles depending on them analyzed
+func watchDir(cfg *conf.Config) error {
+	w, err := watch.New(*searchDir, cfg.ScanOptions(), *debounce)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	p := newParser(cfg, true)
+	regenerate := func(changed []string) {
+		for _, c := range changed {
+			log.Println("changed", c)
+		}
+		if len(changed) > 0 {
+			p.Reload(*searchDir, changed)
+			if *useTypes {
+				if err := p.LoadTypes(*searchDir); err != nil {
+					log.Println(err)
+				}
+			}
+		}
+		p.Parse(false)
+		reportWarnings(p)
+		for _, s := range p.Stale() {
+			log.Println("update", s)
+		}
+		reportOrphans(cfg, p)
+		if len(*out) > 0 {
+			if err := p.WriteSpec(*out, *specVersion); err != nil {
+				log.Println(err)
+			}
+		} else {
+			if err := p.Save(); err != nil {
+				log.Println(err)
+			}
+			if err := p.WriteOutputs(); err != nil {
+				log.Println(err)
+			}
+		}
+		saveCache(p)
+		// own writes are not changes
+		w.Seen(p.Hashes())
+	}
+
+	regenerate(nil)
+	log.Println("watching", *searchDir)
+	return w.Run(regenerate)
+}
+
+// reportOrphans print functions with @Router not routed if orphans is report
+func reportOrphans(cfg *conf.Config, p *parser.Parser) int {
+	if cfg.Orphans != conf.OrphansReport {
+		return 0
+	}
+	orphans := p.Orphans()
+	for _, o := range orphans {
+		fmt.Fprintf(os.Stderr, "not routed: %s\n", o)
+	}
+	return len(orphans)
+}
+
+// reportWarnings print routes skipped by parser
+func reportWarnings(p *parser.Parser) {
+	for _, w := range p.Warnings() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+}
+
+// saveCache save cache if used, after files are saved
+func saveCache(p *parser.Parser) {
+	if err := p.SaveCache(); err != nil {
+		log.Println(err)
+	}
+}
+
+// loadConfig load config file, overridden by flags
+func loadConfig() (*conf.Config, error) {
+	var cfg *conf.Config
+	var err error
+	if len(*configFile) > 0 {
+		cfg, err = conf.LoadFile(*configFile)
+	} else {
+		cfg, err = conf.Load(*searchDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(*specifyFunc) > 0 {
+		cfg.Func = *specifyFunc
+	}
+	if len(*orphans) > 0 {
+		cfg.Orphans = *orphans
+	}
+	if *workers > 0 {
+		cfg.Workers = *workers
+	}
+	if cfg.Info == nil {
+		cfg.Info = map[string]string{}
+	}
+	for k, v := range *info {
+		cfg.Info[k] = v
+	}
+	cfg.Security = append(cfg.Security, *securities...)
+	// output paths are relative to dir
+	for i, o := range cfg.Outputs {
+		if !filepath.IsAbs(o.Path) {
+			cfg.Outputs[i].Path = filepath.Join(*searchDir, o.Path)
+		}
 	}
+	return cfg, nil
 }
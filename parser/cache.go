@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/hocv/gin-swagger-gen/lib/cache"
+	"github.com/hocv/gin-swagger-gen/lib/common"
+	"github.com/hocv/gin-swagger-gen/lib/file"
+	"github.com/hocv/gin-swagger-gen/parser/comment"
+	"github.com/pkg/errors"
+)
+
+// UseCache load cache from path, must be called before ScanDir.
+// unchanged files are parsed on demand, and handlers whose files are unchanged are not analyzed
+func (parser *Parser) UseCache(path string) {
+	parser.cache = cache.Load(path, parser.cacheVersion())
+	parser.proj.SetCache(parser.cache)
+}
+
+// cacheVersion version of generator and config, cache of other versions is ignored
+func (parser *Parser) cacheVersion() string {
+	version, revision := "(devel)", false
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Version
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" || s.Key == "vcs.modified" {
+				version += " " + s.Value
+				revision = revision || s.Key == "vcs.revision"
+			}
+		}
+	}
+	// dev builds without vcs info differ by executable
+	if !revision {
+		version += " " + executableStamp()
+	}
+	cfg, _ := json.Marshal(parser.conf)
+	return fmt.Sprintf("%s %s", version, file.Hash(cfg))
+}
+
+// executableStamp size and modification time of executable,
+// unique if unknown so that cache is not used
+func executableStamp() string {
+	fi, err := os.Stat(executable)
+	if err != nil {
+		return fmt.Sprintf("unknown %d", time.Now().UnixNano())
+	}
+	return fmt.Sprintf("%d %d", fi.Size(), fi.ModTime().UnixNano())
+}
+
+var executable, _ = os.Executable()
+
+// analyze parse handle, or restore generated comment of last Parse or cache
+// if files the handle depends on are unchanged
+func (parser *Parser) analyze(hdl *handle, digests map[string]string) {
+	if hdl.DstDecl == nil {
+		hdl.Parse()
+		return
+	}
+
+	bs, _ := json.Marshal(hdl.Cmt)
+	recv := ""
+	if decl := hdl.DstDecl; decl.Recv != nil && len(decl.Recv.List) > 0 {
+		recv = strings.Trim(common.ToStr(decl.Recv.List[0].Type), "*") + "."
+	}
+	hdl.cacheKey = fmt.Sprintf("%s%s %s", recv, hdl.DstDecl.Name.Name, file.Hash(bs))
+	if hdl.proj.HasTypes() {
+		hdl.cacheKey += " types"
+	}
+
+	if bs, ok := parser.last[hdl.dstFile.Path()+" "+hdl.cacheKey]; ok &&
+		!parser.proj.DependsOn(hdl.curPkg, parser.changed) && hdl.restore(bs) {
+		return
+	}
+	c := parser.cache
+	if c == nil {
+		hdl.Parse()
+		hdl.generated, _ = json.Marshal(hdl.Cmt)
+		return
+	}
+	if _, ok := digests[hdl.curPkg]; !ok {
+		digests[hdl.curPkg] = parser.proj.Digest(hdl.curPkg)
+	}
+	if bs, ok := c.Handler(hdl.dstFile.Path(), hdl.cacheKey, digests[hdl.curPkg]); ok && hdl.restore(bs) {
+		return
+	}
+
+	hdl.Parse()
+	hdl.generated, _ = json.Marshal(hdl.Cmt)
+}
+
+// restore generated comment of handle
+func (hdl *handle) restore(bs []byte) bool {
+	var cmt comment.Comment
+	if err := json.Unmarshal(bs, &cmt); err != nil {
+		return false
+	}
+	hdl.Cmt, hdl.generated, hdl.cached = &cmt, bs, true
+	return true
+}
+
+// keep generated comments of handles for next Parse, changes are consumed
+func (parser *Parser) keep() {
+	parser.last = map[string][]byte{}
+	for _, hdl := range parser.hdls {
+		if len(hdl.generated) > 0 {
+			parser.last[hdl.dstFile.Path()+" "+hdl.cacheKey] = hdl.generated
+		}
+	}
+	parser.changed = nil
+}
+
+// SaveCache save index of files and generated comments to cache,
+// hashes are of saved files if called after Save
+func (parser *Parser) SaveCache() error {
+	c := parser.cache
+	if c == nil {
+		return nil
+	}
+	c.Reset(parser.proj.Files())
+	digests := map[string]string{}
+	for _, hdl := range parser.hdls {
+		if len(hdl.generated) == 0 {
+			continue
+		}
+		if _, ok := digests[hdl.curPkg]; !ok {
+			digests[hdl.curPkg] = parser.proj.Digest(hdl.curPkg)
+		}
+		c.SetHandler(hdl.dstFile.Path(), hdl.cacheKey, cache.Handler{
+			Deps:    digests[hdl.curPkg],
+			Comment: hdl.generated,
+		})
+	}
+	if err := c.Save(); err != nil {
+		return errors.Wrap(err, "save cache")
+	}
+	return nil
+}
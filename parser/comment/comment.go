@@ -19,8 +19,10 @@ type Comment struct {
 	accept      []string
 	produce     []string
 	route       Route
+	others      []Route // other routes of the same handle
 	params      Params
 	resp        map[int]Resp
+	success     map[int]bool // status codes of @Success, 200 if empty
 }
 
 func New(summary, routeBase, routePath, method string) *Comment {
@@ -65,11 +67,13 @@ func (c *Comment) Decs() []string {
 		desc = append(desc, p.Decs())
 	}
 
-	for _, r := range c.resp {
-		desc = append(desc, r.Decs())
+	for _, r := range c.Resps() {
+		desc = append(desc, r.decs(c.isSuccess(r.Code)))
 	}
 
-	desc = append(desc, c.route.Decs())
+	for _, r := range c.Routes() {
+		desc = append(desc, r.Decs())
+	}
 	return desc
 }
 
@@ -87,23 +91,154 @@ func (c *Comment) Merge(decl *dst.FuncDecl) bool {
 	}
 
 	cur := c.Decs()
+	return !SameLines(old, cur)
+}
+
+// SameLines a and b contain same lines, ignore order
+func SameLines(a, b []string) bool {
 	dic := make(map[string]struct{})
-	for _, s := range cur {
+	for _, s := range b {
 		dic[s] = struct{}{}
 	}
-
-	for _, s := range old {
+	for _, s := range a {
 		if _, ok := dic[s]; !ok {
-			return true
+			return false
 		}
+		delete(dic, s)
 	}
+	return len(dic) == 0
+}
 
-	return false
+// Summary summary of comment
+func (c *Comment) Summary() string {
+	return c.summary
+}
+
+// Description description lines joined with new line
+func (c *Comment) Description() string {
+	return strings.Join(c.description, "\n")
+}
+
+// Tags tags of comment
+func (c *Comment) Tags() []string {
+	if len(c.tags) == 0 {
+		return nil
+	}
+	return strings.Split(c.tags, ",")
+}
+
+// ID operation id
+func (c *Comment) ID() string {
+	return c.id
+}
+
+// Accept accept types, sorted and unique
+func (c *Comment) Accept() []string {
+	return trim(c.accept)
+}
+
+// Produce produce types, sorted and unique
+func (c *Comment) Produce() []string {
+	return trim(c.produce)
+}
+
+// Params params sorted by type
+func (c *Comment) Params() Params {
+	sort.Sort(c.params)
+	return c.params
+}
+
+// Resps responses sorted by code
+func (c *Comment) Resps() []Resp {
+	var resps []Resp
+	for _, r := range c.resp {
+		resps = append(resps, r)
+	}
+	sort.Slice(resps, func(i, j int) bool {
+		return resps[i].Code < resps[j].Code
+	})
+	return resps
+}
+
+// Route route of comment
+func (c *Comment) Route() Route {
+	return c.route
+}
+
+// Routes route of comment and other routes of the same handle
+func (c *Comment) Routes() []Route {
+	return append([]Route{c.route}, c.others...)
+}
+
+// Combine comments of a handle bound to several routes into one,
+// with a @Router for each route, path params of any route and other params common to all
+func Combine(cmts ...*Comment) *Comment {
+	if len(cmts) == 1 {
+		return cmts[0]
+	}
+	c := *cmts[0]
+	c.params, c.accept, c.produce = nil, nil, nil
+	c.resp = map[int]Resp{}
+	var tags []string
+	var routes []Route
+	seen := map[Route]bool{}
+	for _, cmt := range cmts {
+		tags = append(tags, cmt.Tags()...)
+		c.accept = append(c.accept, cmt.accept...)
+		c.produce = append(c.produce, cmt.produce...)
+		for _, r := range cmt.resp {
+			c.resp[r.Code] = r
+		}
+		for _, r := range cmt.Routes() {
+			if !seen[r] {
+				seen[r] = true
+				routes = append(routes, r)
+			}
+		}
+	}
+	c.accept, c.produce = trim(c.accept), trim(c.produce)
+	c.tags = strings.Join(trim(tags), ",")
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].RoutePath != routes[j].RoutePath {
+			return routes[i].RoutePath < routes[j].RoutePath
+		}
+		return routes[i].RouteMethod < routes[j].RouteMethod
+	})
+	c.route, c.others = routes[0], routes[1:]
+
+	// every path of @Router declares its path params
+	paths := map[string]bool{}
+	for _, cmt := range cmts {
+		for _, p := range cmt.params {
+			if p.paramType == "path" && !paths[p.Name] {
+				paths[p.Name] = true
+				c.params = append(c.params, p)
+			}
+		}
+	}
+	for _, p := range cmts[0].params {
+		if p.paramType == "path" {
+			continue
+		}
+		common := true
+		for _, cmt := range cmts[1:] {
+			if !cmt.hasParam(p.Name, p.paramType) {
+				common = false
+				break
+			}
+		}
+		if common {
+			c.params = append(c.params, p)
+		}
+	}
+	return &c
 }
 
-func (c *Comment) SetParamRefType(name, refType string) {
+// SetParamRefType set type of param with name in location. e.g. header, id
+func (c *Comment) SetParamRefType(in, name, refType string) {
 	for i, p := range c.params {
-		if p.Name != name {
+		if p.paramType != in || p.Name != name {
 			continue
 		}
 		c.params[i].RefType = refType
@@ -124,6 +259,51 @@ func (c *Comment) AddParam(param Param) {
 	c.params = append(c.params, param)
 }
 
+// SetParam replace param with same name and location, add if not exists
+func (c *Comment) SetParam(param Param) {
+	for i, p := range c.params {
+		if p.paramType == param.paramType && p.Name == param.Name {
+			c.params[i] = param
+			return
+		}
+	}
+	c.params = append(c.params, param)
+}
+
+func (c *Comment) hasParam(name, paramType string) bool {
+	for _, p := range c.params {
+		if p.paramType == paramType && p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetID set operation id
+func (c *Comment) SetID(id string) {
+	c.id = id
+}
+
+// SetTags set tags, comma separated
+func (c *Comment) SetTags(tags string) {
+	c.tags = tags
+}
+
+// SetSuccess set status codes of @Success
+func (c *Comment) SetSuccess(codes []int) {
+	c.success = map[int]bool{}
+	for _, code := range codes {
+		c.success[code] = true
+	}
+}
+
+func (c *Comment) isSuccess(code int) bool {
+	if len(c.success) == 0 {
+		return code == 200
+	}
+	return c.success[code]
+}
+
 func (c *Comment) AddResp(resp Resp) {
 	c.resp[resp.Code] = resp
 }
@@ -159,7 +339,8 @@ func (c *Comment) parseComment(cmt string) {
 			c.produce = append(c.produce, strings.Split(remainder, ",")...)
 		}
 	case "@param":
-		if p, err := parseParam(commentLine); err == nil {
+		// generated param wins over the one in old comment
+		if p, err := parseParam(commentLine); err == nil && !c.hasParam(p.Name, p.paramType) {
 			c.AddParam(p)
 		}
 	case "@tags":
@@ -175,35 +356,49 @@ func (c *Comment) parseComment(cmt string) {
 
 var paramPattern = regexp.MustCompile(`(\S+)[\s]+([\w]+)[\s]+([\S.]+)[\s]+([\w]+)[\s]+"([^"]+)"`)
 
+var formatPattern = regexp.MustCompile(`format\(([^)]+)\)`)
+
 func parseParam(commentLine string) (Param, error) {
 	matches := paramPattern.FindStringSubmatch(commentLine)
 	if len(matches) < 6 {
-		return Param{}, nil
+		return Param{}, errors.New("invalid param")
 	}
 
 	name, paramType, refType, desc := matches[1], matches[2], matches[3], matches[5]
 
+	var p Param
 	switch paramType {
 	case "path":
-		return NewPathParam(name, refType, desc), nil
+		p = NewPathParam(name, refType, desc)
 	case "query":
-		return NewQueryParam(name, refType, desc), nil
+		p = NewQueryParam(name, refType, desc)
+	case "header":
+		p = NewHeaderParam(name, refType, desc)
 	case "body":
-		return NewBodyParam(name, refType, desc), nil
+		p = NewBodyParam(name, refType, desc)
 	case "formData":
-		return NewFormDataParam(name, refType, desc), nil
+		p = NewFormDataParam(name, refType, desc)
+	default:
+		return Param{}, errors.New("not supported type")
+	}
+	if fm := formatPattern.FindStringSubmatch(commentLine); len(fm) > 1 {
+		p.Format = fm[1]
 	}
-	return Param{}, errors.New("not supported type")
+	return p, nil
 }
 
 func trimAndJoin(attr string, arr []string) string {
-	var trim []string
+	return fmt.Sprintf("// @%s %s", attr, strings.Join(trim(arr), ","))
+}
+
+// trim sort and remove duplicate
+func trim(arr []string) (res []string) {
 	sort.Strings(arr)
 	for i, s := range arr {
 		if i > 0 && arr[i] == arr[i-1] {
 			continue
 		}
-		trim = append(trim, s)
+		res = append(res, s)
 	}
-	return fmt.Sprintf("// @%s %s", attr, strings.Join(trim, ","))
+	return
 }
@@ -0,0 +1,76 @@
+package comment
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// commentJSON exported fields of Comment, for cache
+type commentJSON struct {
+	Summary     string      `json:"summary"`
+	Tags        string      `json:"tags,omitempty"`
+	ID          string      `json:"id,omitempty"`
+	Description []string    `json:"description,omitempty"`
+	Accept      []string    `json:"accept,omitempty"`
+	Produce     []string    `json:"produce,omitempty"`
+	Route       Route       `json:"route"`
+	Params      []paramJSON `json:"params,omitempty"`
+	Resps       []Resp      `json:"resps,omitempty"`
+	Success     []int       `json:"success,omitempty"`
+}
+
+type paramJSON struct {
+	Param
+	In string
+}
+
+func (c *Comment) MarshalJSON() ([]byte, error) {
+	cj := commentJSON{
+		Summary:     c.summary,
+		Tags:        c.tags,
+		ID:          c.id,
+		Description: c.description,
+		Accept:      c.accept,
+		Produce:     c.produce,
+		Route:       c.route,
+		Resps:       c.Resps(),
+	}
+	for _, p := range c.params {
+		cj.Params = append(cj.Params, paramJSON{Param: p, In: p.paramType})
+	}
+	for code, ok := range c.success {
+		if ok {
+			cj.Success = append(cj.Success, code)
+		}
+	}
+	sort.Ints(cj.Success)
+	return json.Marshal(cj)
+}
+
+func (c *Comment) UnmarshalJSON(bs []byte) error {
+	var cj commentJSON
+	if err := json.Unmarshal(bs, &cj); err != nil {
+		return err
+	}
+	*c = Comment{
+		summary:     cj.Summary,
+		tags:        cj.Tags,
+		id:          cj.ID,
+		description: cj.Description,
+		accept:      cj.Accept,
+		produce:     cj.Produce,
+		route:       cj.Route,
+		resp:        map[int]Resp{},
+	}
+	for _, p := range cj.Params {
+		p.paramType = p.In
+		c.params = append(c.params, p.Param)
+	}
+	for _, r := range cj.Resps {
+		c.resp[r.Code] = r
+	}
+	if len(cj.Success) > 0 {
+		c.SetSuccess(cj.Success)
+	}
+	return nil
+}
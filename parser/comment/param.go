@@ -8,6 +8,7 @@ type Param struct {
 	paramType   string
 	RefType     string
 	Description string
+	Format      string // e.g. uuid, email
 }
 
 func (p Param) Decs() string {
@@ -17,7 +18,16 @@ func (p Param) Decs() string {
 	if p.paramType == "error" {
 		p.paramType = "string"
 	}
-	return fmt.Sprintf("// @Param %s %s %s %t \"%s\"", p.Name, p.paramType, p.RefType, p.Required, p.Description)
+	decs := fmt.Sprintf("// @Param %s %s %s %t \"%s\"", p.Name, p.paramType, p.RefType, p.Required, p.Description)
+	if len(p.Format) > 0 {
+		decs += fmt.Sprintf(" format(%s)", p.Format)
+	}
+	return decs
+}
+
+// In location of param: path, query, header, body, formData
+func (p Param) In() string {
+	return p.paramType
 }
 
 type Params []Param
@@ -63,6 +73,15 @@ func NewQueryParam(name, refType, desc string) Param {
 	}
 }
 
+func NewHeaderParam(name, refType, desc string) Param {
+	return Param{
+		Name:        name,
+		paramType:   "header",
+		RefType:     refType,
+		Description: desc,
+	}
+}
+
 func NewFormDataParam(name, refType, desc string) Param {
 	return Param{
 		Name:        name,
@@ -79,8 +98,12 @@ type Resp struct {
 }
 
 func (r Resp) Decs() string {
+	return r.decs(r.Code == 200)
+}
+
+func (r Resp) decs(success bool) string {
 	v1 := "// @Failure"
-	if r.Code == 200 {
+	if success {
 		v1 = fmt.Sprintf("// @Success")
 	}
 	v2 := "{object}"
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/dave/dst"
+	"github.com/hocv/gin-swagger-gen/lib/conf"
+	"github.com/hocv/gin-swagger-gen/lib/file"
 )
 
 func parseStmtList(stmts []dst.Stmt, vars map[string]string, fn func(stmt interface{}, vars map[string]string)) {
@@ -19,12 +21,29 @@ func parseStmtList(stmts []dst.Stmt, vars map[string]string, fn func(stmt interf
 		case *dst.BlockStmt:
 			local := copyMap(vars)
 			parseStmtList(stmt.(*dst.BlockStmt).List, local, fn)
+		case *dst.ForStmt:
+			// body is walked once, not evaluated for each iteration
+			forStmt := stmt.(*dst.ForStmt)
+			local := copyMap(vars)
+			fn(forStmt.Init, local)
+			parseStmtList(forStmt.Body.List, local, fn)
+		case *dst.RangeStmt:
+			// fn may evaluate body with values ranged over. e.g. route table
+			rangeStmt := stmt.(*dst.RangeStmt)
+			local := copyMap(vars)
+			fn(rangeStmt, local)
+			parseStmtList(rangeStmt.Body.List, local, fn)
 		default:
 			fn(stmt, vars)
 		}
 	}
 }
 
+// readonly comments in file are not changed. e.g. generated files unless configured
+func readonly(cfg *conf.Config, f *file.File) bool {
+	return f.Generated() && !cfg.Generated
+}
+
 // splitDot split string with dot
 func splitDot(str string) (string, string) {
 	arr := strings.Split(str, ".")
@@ -34,10 +53,15 @@ func splitDot(str string) (string, string) {
 	return arr[0], arr[1]
 }
 
-var routePathReg = regexp.MustCompile(":\\w+")
+var routePathReg = regexp.MustCompile("[:*]\\w+")
+
+var routeWildcardReg = regexp.MustCompile("\\*(\\w+)$")
 
-// fmtRoutePath remove "" and replace : to {}.
-// e.g. "/user/:id" => /user/{id}
+// wildcardDesc description of catch-all path param
+const wildcardDesc = "matches the rest of the path"
+
+// fmtRoutePath remove "" and replace : or * to {}.
+// e.g. "/user/:id" => /user/{id}, "/static/*filepath" => /static/{filepath}
 func fmtRoutePath(r string) string {
 	r = strings.Trim(r, "\"")
 	r = routePathReg.ReplaceAllStringFunc(r, func(s string) string {
@@ -63,6 +87,16 @@ func routePathParams(r string) (params []string) {
 	return
 }
 
+// routeWildcard name of catch-all param in gin route path.
+// e.g. "/static/*filepath" => filepath
+func routeWildcard(r string) string {
+	m := routeWildcardReg.FindStringSubmatch(strings.Trim(r, "\""))
+	if len(m) < 2 {
+		return ""
+	}
+	return m[1]
+}
+
 func copyMap(m map[string]string) map[string]string {
 	cp := make(map[string]string)
 	if m == nil {
@@ -15,6 +15,26 @@ func TestFmtRoutePath(t *testing.T) {
 	}
 }
 
+func TestRouteWildcard(t *testing.T) {
+	arr := []struct {
+		Src      string
+		Path     string
+		Wildcard string
+	}{
+		{Src: `"/static/*filepath"`, Path: "/static/{filepath}", Wildcard: "filepath"},
+		{Src: "/user/:id/*action", Path: "/user/{id}/{action}", Wildcard: "action"},
+		{Src: "/user/:id", Path: "/user/{id}"},
+	}
+	for _, rw := range arr {
+		if p := fmtRoutePath(rw.Src); p != rw.Path {
+			t.Fatalf("%s -> %s, but: %s", rw.Src, rw.Path, p)
+		}
+		if w := routeWildcard(rw.Src); w != rw.Wildcard {
+			t.Fatalf("%s -> wildcard %s, but: %s", rw.Src, rw.Wildcard, w)
+		}
+	}
+}
+
 func TestRoutePathParams2(t *testing.T) {
 	path := []string{
 		"/user/{id}/{aaa}",
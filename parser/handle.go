@@ -9,86 +9,158 @@ import (
 
 	"github.com/dave/dst"
 	"github.com/hocv/gin-swagger-gen/lib/common"
+	"github.com/hocv/gin-swagger-gen/lib/conf"
 	"github.com/hocv/gin-swagger-gen/lib/file"
 	"github.com/hocv/gin-swagger-gen/lib/proj"
 )
 
+// paramOf param a var is read from
+type paramOf struct {
+	in   string // query, path or header
+	name string
+}
+
 type handle struct {
-	proj        *proj.Proj
-	dstFile     *file.File
-	curPkg      string
-	DstDecl     *dst.FuncDecl
-	SrcDecl     *dst.FuncDecl
-	Cmt         *comment.Comment
-	Vars        map[string]string
-	queryParams map[string]string
+	proj      *proj.Proj
+	conf      *conf.Config
+	dstFile   *file.File
+	curPkg    string
+	DstDecl   *dst.FuncDecl // function to comment, nil if only in document. e.g. static files
+	SrcDecl   *dst.FuncDecl // function to analyze, nil if nothing to analyze
+	Cmt       *comment.Comment
+	Vars      map[string]string
+	paramVars map[string]paramOf // key: var of param value. e.g. id of id := c.Query("id")
+	stale     bool               // comment in file differs from generated
+	cacheKey  string             // key of generated comment in cache
+	generated []byte             // generated comment before merge, for cache
+	cached    bool               // generated comment is restored from cache
 }
 
-func newHandle(proj *proj.Proj, f *file.File, dstDecl *dst.FuncDecl, decl *dst.FuncDecl, cmt *comment.Comment) *handle {
+func newHandle(proj *proj.Proj, cfg *conf.Config, f *file.File, dstDecl *dst.FuncDecl, decl *dst.FuncDecl, cmt *comment.Comment) *handle {
 	if decl == nil {
 		decl = dstDecl
 	}
 	vars := make(map[string]string) // key: var ,value: var type or method
 
 	// global vars
-	for k, v := range proj.GetGlobalVar(f.Pkg()) {
+	for k, v := range proj.GetGlobalVar(f.PkgPath()) {
 		vars[k] = v
 	}
 	// vars in function param
-	for k, v := range common.GetFuncParams(decl) {
-		vars[k] = v
+	if decl != nil {
+		for k, v := range common.GetFuncParams(decl) {
+			vars[k] = v
+		}
 	}
 
 	return &handle{
-		proj:        proj,
-		dstFile:     f,
-		curPkg:      f.Pkg(),
-		DstDecl:     dstDecl,
-		SrcDecl:     decl,
-		Cmt:         cmt,
-		Vars:        vars,
-		queryParams: map[string]string{},
+		proj:      proj,
+		conf:      cfg,
+		dstFile:   f,
+		curPkg:    f.PkgPath(),
+		DstDecl:   dstDecl,
+		SrcDecl:   decl,
+		Cmt:       cmt,
+		Vars:      vars,
+		paramVars: map[string]paramOf{},
 	}
 }
 
 func (hdl *handle) Parse() {
+	if hdl.SrcDecl == nil {
+		return
+	}
 	parseStmtList(hdl.SrcDecl.Body.List, hdl.Vars, hdl.parseIterm)
 }
 
-func (hdl *handle) Merge() {
-	if !hdl.Cmt.Merge(hdl.DstDecl) {
+// combineHandles comment of handles with same function, old comment merged
+func combineHandles(hdls []*handle) *comment.Comment {
+	var cmts []*comment.Comment
+	for _, hdl := range hdls {
+		hdl.Cmt.Merge(hdl.DstDecl)
+		cmts = append(cmts, hdl.Cmt)
+	}
+	return comment.Combine(cmts...)
+}
+
+// mergeHandles merge comment of handles with same function to it, a @Router for each route
+func mergeHandles(hdls []*handle) {
+	decl := hdls[0].DstDecl
+	old := decl.Decs.Start.All()
+	cur := combineHandles(hdls).Decs()
+	if len(old) > 0 && comment.SameLines(old, cur) {
 		return
 	}
-	hdl.DstDecl.Decs.Start.Clear()
-	hdl.DstDecl.Decs.Start.Append(hdl.Cmt.Decs()...)
-	hdl.dstFile.Dirty()
+	decl.Decs.Start.Clear()
+	decl.Decs.Start.Append(cur...)
+	hdls[0].dstFile.Dirty()
+	for _, hdl := range hdls {
+		hdl.stale = true
+	}
 }
 
-func (hdl *handle) Print() {
-	hdl.Cmt.Merge(hdl.DstDecl)
-	for _, s := range hdl.Cmt.Decs() {
+func printHandles(hdls []*handle) {
+	for _, s := range combineHandles(hdls).Decs() {
 		fmt.Println(s)
 	}
 }
 
+// groupHandles group handles by function, in order of first appearance.
+// handles only in document, or in readonly files are ignored
+func groupHandles(hdls []*handle) [][]*handle {
+	var groups [][]*handle
+	idx := map[*dst.FuncDecl]int{}
+	for _, hdl := range hdls {
+		if hdl.DstDecl == nil || readonly(hdl.conf, hdl.dstFile) {
+			continue
+		}
+		i, ok := idx[hdl.DstDecl]
+		if !ok {
+			i = len(groups)
+			idx[hdl.DstDecl] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], hdl)
+	}
+	return groups
+}
+
+// typeOf exact type of expr, false if type info not loaded
+func (hdl *handle) typeOf(expr dst.Expr) (string, bool) {
+	t, ok := hdl.proj.TypeOf(hdl.dstFile, expr)
+	return strings.TrimLeft(t, "*"), ok
+}
+
 func (hdl *handle) parseIterm(stmt interface{}, vars map[string]string) {
 	fn := func(parser handleParser, v string) {
 		call, err := common.GetCallExprByVarName(stmt, v)
 		if err != nil {
 			return
 		}
+		// inline getter, e.g. strconv.Atoi(c.Param("id"))
+		for _, arg := range call.Args {
+			inner, ok := arg.(*dst.CallExpr)
+			if !ok {
+				continue
+			}
+			if ip, ok := handleParserOf(common.ToStr(inner.Fun)); ok {
+				ip(hdl, vars, common.ToStr(inner), inner)
+			}
+		}
 		parser(hdl, vars, v, call)
 	}
 
 	vs := hdl.proj.GetVarsFromStmt(stmt, hdl.curPkg, vars)
 	for v, t := range vs {
-		_, sel := splitDot(t)
-
-		parser, ok := handleParsers[sel]
-		if !ok {
-			parser, ok = handleParsers[t]
+		// response wrapper in config, before built-in ones of same name. e.g. response.OK(c, data), resp.JSON(c, data)
+		if w, ok := hdl.conf.Wrapper(t); ok {
+			if call, err := common.GetCallExprByVarName(stmt, v); err == nil {
+				hdl.parseWrapper(w, vars, call)
+			}
+			continue
 		}
 
+		parser, ok := handleParserOf(t)
 		if ok {
 			fn(parser, v)
 			continue
@@ -123,7 +195,7 @@ func (hdl *handle) parseIterm(stmt interface{}, vars map[string]string) {
 			continue
 		}
 
-		ffs := hdl.proj.GetFunc(hdl.curPkg, t)
+		ffs := hdl.proj.GetFuncIn(hdl.dstFile, t)
 		if len(ffs) == 0 {
 			continue
 		}
@@ -138,7 +210,7 @@ func (hdl *handle) parseIterm(stmt interface{}, vars map[string]string) {
 				for i, s := range fps {
 					nvs[s] = ps[i]
 				}
-				fh := newHandle(hdl.proj, f, hdl.DstDecl, fnd, hdl.Cmt)
+				fh := newHandle(hdl.proj, hdl.conf, f, hdl.DstDecl, fnd, hdl.Cmt)
 				for nk, nv := range nvs {
 					if ov, ok := vars[nv]; ok {
 						fh.Vars[nk] = ov
@@ -152,6 +224,20 @@ func (hdl *handle) parseIterm(stmt interface{}, vars map[string]string) {
 
 type handleParser func(hdl *handle, vars map[string]string, val string, call *dst.CallExpr)
 
+// handleParserOf parser of function called.
+// e.g. c.Query, strconv.Atoi, c.Request.Header.Get
+func handleParserOf(fn string) (handleParser, bool) {
+	_, sel := splitDot(fn)
+	parser, ok := handleParsers[sel]
+	if !ok {
+		parser, ok = handleParsers[fn]
+	}
+	if idx := strings.Index(fn, "."); !ok && idx > 0 {
+		parser, ok = handleParsers[fn[idx+1:]]
+	}
+	return parser, ok
+}
+
 var handleParsers = map[string]handleParser{
 	"strconv.Atoi":       parseStrConv("integer"),
 	"strconv.ParseInt":   parseStrConv("integer"),
@@ -164,6 +250,11 @@ var handleParsers = map[string]handleParser{
 	"ShouldBindXML":      parseBind("xml"),
 	"BindYAML":           parseBind("yaml"),
 	"ShouldBindYAML":     parseBind("yaml"),
+	"Bind":               parseBindWith("any"),
+	"ShouldBind":         parseBindWith("any"),
+	"BindWith":           parseBindWith(""),
+	"ShouldBindWith":     parseBindWith(""),
+	"ShouldBindBodyWith": parseBindWith(""),
 	"Query":              parseQuery(""),
 	"BindQuery":          parseQuery("BindQuery"),
 	"ShouldBindQuery":    parseQuery("ShouldBindQuery"),
@@ -181,6 +272,13 @@ var handleParsers = map[string]handleParser{
 	"PostFormMap":        parseForm(""),
 	"GetPostFormMap":     parseForm(""),
 	"FormFile":           parseForm(""),
+	"Param":              parsePath(""),
+	"BindUri":            parsePath("BindUri"),
+	"ShouldBindUri":      parsePath("ShouldBindUri"),
+	"GetHeader":          parseHeader(""),
+	"Request.Header.Get": parseHeader(""),
+	"BindHeader":         parseHeader("BindHeader"),
+	"ShouldBindHeader":   parseHeader("ShouldBindHeader"),
 	"HTML":               parseProduce("html"),
 	"IndentedJSON":       parseProduce("json"),
 	"SecureJSON":         parseProduce("json"),
@@ -262,11 +360,11 @@ var stateCode = map[string]int{
 func parseStrConv(dstType string) handleParser {
 	return func(hdl *handle, vars map[string]string, val string, call *dst.CallExpr) {
 		p := common.ToStr(call.Args[0])
-		name, ok := hdl.queryParams[p]
+		param, ok := hdl.paramVars[p]
 		if !ok {
 			return
 		}
-		hdl.Cmt.SetParamRefType(name, dstType)
+		hdl.Cmt.SetParamRefType(param.in, param.name, dstType)
 	}
 }
 
@@ -276,16 +374,86 @@ func parseBind(bindType string) handleParser {
 			return
 		}
 
-		name := common.ToStr(call.Args[0])
-		refType, ok := vars[name]
-		if !ok {
+		if hdl.bindBody(vars, call.Args[0]) {
+			hdl.Cmt.AddAccept(bindType)
+		}
+	}
+}
+
+// bindingTypes binding of gin to bind type. e.g. binding.JSON
+var bindingTypes = map[string]string{
+	"JSON":          "json",
+	"XML":           "xml",
+	"YAML":          "yaml",
+	"ProtoBuf":      "protobuf",
+	"MsgPack":       "msgpack",
+	"Form":          "form",
+	"FormPost":      "x-www-form-urlencoded",
+	"FormMultipart": "multipart/form-data",
+	"Query":         "query",
+	"Header":        "header",
+}
+
+// parseBindWith bind type is got from binding arg if empty,
+// "any" is decided by content type of request
+func parseBindWith(bindType string) handleParser {
+	return func(hdl *handle, vars map[string]string, val string, call *dst.CallExpr) {
+		if len(call.Args) == 0 {
 			return
 		}
 
-		param := comment.NewBodyParam(name, refType, "")
-		hdl.Cmt.AddParam(param)
-		hdl.Cmt.AddAccept(bindType)
+		bt := bindType
+		if len(bt) == 0 {
+			if len(call.Args) < 2 {
+				return
+			}
+			_, sel := splitDot(common.ToStr(call.Args[1]))
+			t, ok := bindingTypes[sel]
+			if !ok {
+				return
+			}
+			bt = t
+		}
+
+		isGet := hdl.Cmt.Route().RouteMethod == "GET"
+		switch {
+		case bt == "query", bt == "form" && isGet, bt == "any" && isGet:
+			hdl.bindParams(vars, call.Args[0], common.GetFormTag, comment.NewQueryParam)
+		case bt == "header":
+			hdl.bindParams(vars, call.Args[0], common.GetHeaderTag, comment.NewHeaderParam)
+		case bt == "form":
+			hdl.bindParams(vars, call.Args[0], common.GetFormTag, comment.NewFormDataParam)
+			hdl.Cmt.AddAccept("x-www-form-urlencoded")
+		case bt == "x-www-form-urlencoded", bt == "multipart/form-data":
+			hdl.bindParams(vars, call.Args[0], common.GetFormTag, comment.NewFormDataParam)
+			hdl.Cmt.AddAccept(bt)
+		case bt == "any":
+			if hdl.bindBody(vars, call.Args[0]) {
+				hdl.Cmt.AddAccept("json")
+				hdl.Cmt.AddAccept("xml")
+				hdl.Cmt.AddAccept("x-www-form-urlencoded")
+			}
+		default:
+			if hdl.bindBody(vars, call.Args[0]) {
+				hdl.Cmt.AddAccept(bt)
+			}
+		}
+	}
+}
+
+// bindBody add body param of arg, false if type of arg is unknown
+func (hdl *handle) bindBody(vars map[string]string, arg dst.Expr) bool {
+	name := common.ToStr(arg)
+	refType, ok := vars[name]
+	if t, tok := hdl.typeOf(arg); tok {
+		refType, ok = t, true
 	}
+	if !ok {
+		return false
+	}
+
+	hdl.Cmt.AddParam(comment.NewBodyParam(name, refType, ""))
+	return true
 }
 
 func parseQuery(queryType string) handleParser {
@@ -294,54 +462,134 @@ func parseQuery(queryType string) handleParser {
 			return
 		}
 
-		name, refType, desc, ok := "", "string", "", false
 		if strings.Contains(queryType, "Bind") {
-			name = common.ToStr(call.Args[0])
-			refType, ok = vars[name]
-			if !ok {
-				return
-			}
-			pkg, struName := splitDot(refType)
-			if len(pkg) == 0 {
-				pkg = hdl.curPkg
+			hdl.bindParams(vars, call.Args[0], common.GetFormTag, comment.NewQueryParam)
+			return
+		}
+
+		name, desc := common.BasicLitValue(call.Args[0]), ""
+		if strings.Contains(queryType, "Default") && len(call.Args) > 1 {
+			desc = fmt.Sprintf("default %s", common.BasicLitValue(call.Args[len(call.Args)-1]))
+		}
+		vars[val] = "string"
+		hdl.paramVars[val] = paramOf{in: "query", name: name}
+
+		param := comment.NewQueryParam(name, "string", desc)
+		hdl.Cmt.AddParam(param)
+	}
+}
+
+func parsePath(pathType string) handleParser {
+	return func(hdl *handle, vars map[string]string, val string, call *dst.CallExpr) {
+		if len(call.Args) == 0 {
+			return
+		}
+
+		if strings.Contains(pathType, "Bind") {
+			hdl.bindParams(vars, call.Args[0], common.GetUriTag, comment.NewPathParam)
+			return
+		}
+
+		// path param is added by route, only track var for type
+		name := common.BasicLitValue(call.Args[0])
+		if len(name) == 0 {
+			return
+		}
+		vars[val] = "string"
+		hdl.paramVars[val] = paramOf{in: "path", name: name}
+	}
+}
+
+func parseHeader(headerType string) handleParser {
+	return func(hdl *handle, vars map[string]string, val string, call *dst.CallExpr) {
+		if len(call.Args) == 0 {
+			return
+		}
+
+		if strings.Contains(headerType, "Bind") {
+			hdl.bindParams(vars, call.Args[0], common.GetHeaderTag, comment.NewHeaderParam)
+			return
+		}
+
+		name := common.BasicLitValue(call.Args[0])
+		if len(name) == 0 {
+			return
+		}
+		vars[val] = "string"
+		hdl.paramVars[val] = paramOf{in: "header", name: name}
+		hdl.Cmt.AddParam(comment.NewHeaderParam(name, "string", ""))
+	}
+}
+
+// bindParams add a param for each field of struct bound by arg,
+// name of param is got from tag, field name if no tag like gin
+func (hdl *handle) bindParams(vars map[string]string, arg dst.Expr, tagFn func(string) string,
+	newParam func(name, refType, desc string) comment.Param) {
+	refType, ok := vars[common.ToStr(arg)]
+	if t, tok := hdl.typeOf(arg); tok {
+		refType, ok = t, true
+	}
+	if !ok {
+		return
+	}
+	hdl.bindFields(refType, tagFn, newParam, map[string]bool{})
+}
+
+// bindFields add params of struct fields, embedded structs are bound as their fields like gin
+func (hdl *handle) bindFields(refType string, tagFn func(string) string,
+	newParam func(name, refType, desc string) comment.Param, seen map[string]bool) {
+	refType = strings.Trim(refType, "*")
+	if seen[refType] {
+		return
+	}
+	seen[refType] = true
+	// type is qualified as used in file of handle
+	stru, err := hdl.proj.GetStructIn(hdl.dstFile, refType)
+	if err != nil {
+		return
+	}
+	qualifier, _ := splitDot(refType)
+	for _, field := range stru.Fields.List {
+		ft := common.ToStr(field.Type)
+		tag, format := "", ""
+		required := false
+		if field.Tag != nil {
+			tag = tagFn(field.Tag.Value)
+			required = common.GetTagBindingRequired(field.Tag.Value)
+			format = common.GetTagBindingFormat(field.Tag.Value)
+		}
+		if tag == "-" {
+			continue
+		}
+		if len(field.Names) == 0 {
+			// embedded, type in package of struct. e.g. Page -> book.Page
+			et := strings.Trim(ft, "*")
+			if len(qualifier) > 0 && !strings.Contains(et, ".") {
+				et = qualifier + "." + et
 			}
-			stru, err := hdl.proj.GetStruct(pkg, struName)
-			if err != nil {
-				return
+			hdl.bindFields(et, tagFn, newParam, seen)
+			continue
+		}
+		for _, ident := range field.Names {
+			fieldName := tag
+			if len(fieldName) == 0 {
+				fieldName = ident.Name
 			}
-			for _, field := range stru.Fields.List {
-				if len(field.Names) == 0 {
+			param := newParam(fieldName, ft, "")
+			param.Required = required
+			param.Format = format
+			// path param is added by route as string
+			if param.In() == "path" {
+				param.Required = true
+				if !strings.Contains(hdl.Cmt.Route().RoutePath, "{"+fieldName+"}") {
 					continue
 				}
-				ft := common.ToStr(field.Type)
-				tag := ""
-				required := false
-				if field.Tag != nil {
-					tag = common.GetFormTag(field.Tag.Value)
-					required = common.GetTagBindingRequired(field.Tag.Value)
-				}
-				for _, ident := range field.Names {
-					fieldName := tag
-					if len(name) == 0 {
-						fieldName = common.SnakeCase(ident.Name)
-					}
-					param := comment.NewQueryParam(fieldName, ft, "")
-					param.Required = required
-					hdl.Cmt.AddParam(param)
-				}
-			}
-			return
-		} else {
-			name = common.BasicLitValue(call.Args[0])
-			if strings.Contains(queryType, "Default") && len(call.Args) > 1 {
-				desc = fmt.Sprintf("default %s", common.BasicLitValue(call.Args[len(call.Args)-1]))
+				param.Description = fieldName
+				hdl.Cmt.SetParam(param)
+				continue
 			}
-			vars[val] = "string"
-			hdl.queryParams[val] = name
+			hdl.Cmt.AddParam(param)
 		}
-
-		param := comment.NewQueryParam(name, refType, desc)
-		hdl.Cmt.AddParam(param)
 	}
 }
 
@@ -388,16 +636,50 @@ func parseProduce(produceType string) handleParser {
 			Type: produceType,
 		}
 
-		lastArg := common.ToStr(call.Args[1])
-		if val, ok := vars[lastArg]; ok {
-			r.Type = val
-		} else {
-			nv := hdl.proj.GetVarsFromStmt(call.Args[1], hdl.curPkg, vars)
-			if t, ok := nv["_"]; ok {
-				r.Type = t
-			}
+		if t := hdl.exprType(vars, call.Args[1]); len(t) > 0 {
+			r.Type = t
 		}
 
 		hdl.Cmt.AddResp(r)
 	}
 }
+
+// parseWrapper response of wrapper function in config
+func (hdl *handle) parseWrapper(w conf.Wrapper, vars map[string]string, call *dst.CallExpr) {
+	r := comment.Resp{
+		Code: w.Code,
+		Type: w.Type,
+	}
+	if len(r.Type) == 0 || strings.Contains(r.Type, "%s") {
+		arg := w.DataArg(len(call.Args))
+		if arg < 0 || arg >= len(call.Args) {
+			return
+		}
+		t := hdl.exprType(vars, call.Args[arg])
+		if len(t) == 0 {
+			return
+		}
+		r.Type = t
+		if len(w.Type) > 0 {
+			r.Type = fmt.Sprintf(w.Type, t)
+		}
+	}
+	hdl.Cmt.AddProduce(w.Produce)
+	hdl.Cmt.AddResp(r)
+}
+
+// exprType type of expr, empty if unknown
+func (hdl *handle) exprType(vars map[string]string, expr dst.Expr) string {
+	var typ string
+	if val, ok := vars[common.ToStr(expr)]; ok {
+		typ = val
+	} else {
+		nv := hdl.proj.GetVarsFromStmt(expr, hdl.curPkg, vars)
+		typ = nv["_"]
+	}
+	// model composition can not be got from type info
+	if t, ok := hdl.typeOf(expr); ok && !strings.Contains(typ, "{") {
+		typ = t
+	}
+	return typ
+}
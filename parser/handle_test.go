@@ -2,8 +2,11 @@ package parser
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/hocv/gin-swagger-gen/lib/conf"
 	"github.com/hocv/gin-swagger-gen/lib/file"
 	"github.com/hocv/gin-swagger-gen/lib/proj"
 )
@@ -27,7 +30,7 @@ func TestHandleAccept(t *testing.T) {
 
 	ffnd := p.GetFunc("test", "handleTest")
 
-	rh := newRoute(p, "Default", "handleAccept")
+	rh := newRoute(p, "Default", &conf.Config{Func: "handleAccept"})
 	for f, fnd := range ffnd {
 		rh.Parse(f, fnd)
 		if len(rh.Handles) != 1 {
@@ -64,7 +67,7 @@ func TestHandleProduct(t *testing.T) {
 		return
 	}
 
-	rh := newRoute(p, "Default", "handleProduct")
+	rh := newRoute(p, "Default", &conf.Config{Func: "handleProduct"})
 
 	for f, fnd := range ffnd {
 		rh.Parse(f, fnd)
@@ -104,7 +107,7 @@ func TestHandleRecursive(t *testing.T) {
 		return
 	}
 
-	rh := newRoute(p, "Default", "handleRecursive")
+	rh := newRoute(p, "Default", &conf.Config{Func: "handleRecursive"})
 
 	for f, fnd := range ffnd {
 		rh.Parse(f, fnd)
@@ -118,3 +121,373 @@ func TestHandleRecursive(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleHeader(t *testing.T) {
+	p := proj.New()
+	files := []string{
+		"./test/handle.go",
+		"./test/model/book/book.go",
+		"./test/model/price/price.go",
+	}
+
+	for _, s := range files {
+		f, err := file.New(s)
+		if err != nil {
+			t.Fatal(f)
+			return
+		}
+		p.AddFile(f)
+	}
+
+	ffnd := p.GetFunc("test", "handleTest")
+
+	rh := newRoute(p, "Default", &conf.Config{Func: "handleHeader"})
+	for f, fnd := range ffnd {
+		rh.Parse(f, fnd)
+		if len(rh.Handles) != 1 {
+			t.Fatal()
+		}
+
+		rh.Handles[0].Parse()
+		headers := map[string]bool{}
+		for _, param := range rh.Handles[0].Cmt.Params() {
+			if param.In() == "header" {
+				headers[param.Name] = param.Required
+			}
+		}
+		want := map[string]bool{
+			"X-Tenant-ID":     false,
+			"Accept-Language": false,
+			"Authorization":   true,
+			"X-Trace-ID":      false,
+		}
+		for name, required := range want {
+			if r, ok := headers[name]; !ok || r != required {
+				t.Fatalf("header %s: %v", name, headers)
+			}
+		}
+		for _, s := range rh.Handles[0].Cmt.Decs() {
+			fmt.Println(s)
+		}
+	}
+}
+
+func TestHandlePath(t *testing.T) {
+	p := proj.New()
+	files := []string{
+		"./test/handle.go",
+		"./test/model/book/book.go",
+		"./test/model/price/price.go",
+	}
+
+	for _, s := range files {
+		f, err := file.New(s)
+		if err != nil {
+			t.Fatal(f)
+			return
+		}
+		p.AddFile(f)
+	}
+
+	ffnd := p.GetFunc("test", "handleTest")
+
+	want := map[string]map[string]string{
+		"handlePath": {"id": "integer", "page": "integer"},
+		"handleUri":  {"uid": "string uuid"},
+	}
+	for fn, params := range want {
+		rh := newRoute(p, "Default", &conf.Config{Func: fn})
+		for f, fnd := range ffnd {
+			rh.Parse(f, fnd)
+			if len(rh.Handles) != 1 {
+				t.Fatal()
+			}
+
+			rh.Handles[0].Parse()
+			got := map[string]string{}
+			for _, param := range rh.Handles[0].Cmt.Params() {
+				if param.In() == "path" {
+					got[param.Name] = strings.TrimSpace(param.RefType + " " + param.Format)
+				}
+			}
+			for name, typ := range params {
+				if got[name] != typ {
+					t.Fatalf("%s: path param %s is %q, want %q", fn, name, got[name], typ)
+				}
+			}
+			for _, s := range rh.Handles[0].Cmt.Decs() {
+				fmt.Println(s)
+			}
+		}
+	}
+}
+
+func TestHandleBind(t *testing.T) {
+	p := proj.New()
+	files := []string{
+		"./test/handle.go",
+		"./test/model/book/book.go",
+		"./test/model/price/price.go",
+	}
+
+	for _, s := range files {
+		f, err := file.New(s)
+		if err != nil {
+			t.Fatal(f)
+			return
+		}
+		p.AddFile(f)
+	}
+
+	ffnd := p.GetFunc("test", "handleTest")
+
+	want := map[string]string{
+		"GET /group/hdl_bind":       "query:Page query:kw",
+		"POST /group/hdl_bind":      "body:s json,x-www-form-urlencoded,xml",
+		"POST /group/hdl_bind_with": "body:p formData:Page formData:kw multipart/form-data,yaml",
+	}
+	for _, fn := range []string{"handleBind", "handleBindWith"} {
+		rh := newRoute(p, "Default", &conf.Config{Func: fn})
+		for f, fnd := range ffnd {
+			rh.Parse(f, fnd)
+		}
+		for _, hdl := range rh.Handles {
+			hdl.Parse()
+			route := hdl.Cmt.Route()
+			var got []string
+			for _, param := range hdl.Cmt.Params() {
+				got = append(got, param.In()+":"+param.Name)
+			}
+			sort.Strings(got)
+			accept := hdl.Cmt.Accept()
+			sort.Strings(accept)
+			got = append(got, strings.Join(accept, ","))
+			key := route.RouteMethod + " " + route.RoutePath
+			if w := want[key]; strings.TrimSpace(strings.Join(got, " ")) != w {
+				t.Fatalf("%s: %v, want %s", key, got, w)
+			}
+			for _, s := range hdl.Cmt.Decs() {
+				fmt.Println(s)
+			}
+		}
+	}
+}
+
+func TestHandleConfig(t *testing.T) {
+	p := proj.New()
+	files := []string{
+		"./test/handle.go",
+		"./test/model/book/book.go",
+		"./test/model/price/price.go",
+	}
+
+	for _, s := range files {
+		f, err := file.New(s)
+		if err != nil {
+			t.Fatal(f)
+			return
+		}
+		p.AddFile(f)
+	}
+
+	ffnd := p.GetFunc("test", "handleTest")
+
+	cfg := &conf.Config{
+		Func:    "handleWrapper",
+		Tag:     conf.TagPackage,
+		Success: []int{200, 201},
+		Wrappers: []conf.Wrapper{
+			{Func: "respond", Code: 201, Type: "Resp{data=%s}"},
+		},
+	}
+	rh := newRoute(p, "Default", cfg)
+	for f, fnd := range ffnd {
+		rh.Parse(f, fnd)
+		if len(rh.Handles) != 1 {
+			t.Fatal()
+		}
+
+		rh.Handles[0].Parse()
+		decs := rh.Handles[0].Cmt.Decs()
+		want := map[string]bool{
+			"// @Tags test": true,
+			"// @Success 201 {object} Resp{data=book.Book}": true,
+		}
+		for _, s := range decs {
+			fmt.Println(s)
+			delete(want, s)
+		}
+		if len(want) > 0 {
+			t.Fatal(want)
+		}
+	}
+}
+
+func TestWrapperBuiltinName(t *testing.T) {
+	codes := []string{`
+package test
+
+import (
+	"github.com/gin-gonic/gin"
+	"example.com/resp"
+)
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+func route() {
+	g := gin.Default()
+	g.GET("/user", getUser)
+}
+
+func getUser(c *gin.Context) {
+	u := User{}
+	resp.JSON(c, u)
+}
+`, `
+package resp
+
+import "github.com/gin-gonic/gin"
+
+type Body struct {
+	Data interface{} ` + "`json:\"data\"`" + `
+}
+
+func JSON(c *gin.Context, data interface{}) {
+	c.JSON(200, Body{Data: data})
+}
+`}
+	p := proj.New()
+	for _, code := range codes {
+		f, err := file.New(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.AddFile(f)
+	}
+	cfg := &conf.Config{
+		Wrappers: []conf.Wrapper{{Func: "resp.JSON", Type: "resp.Body{data=%s}"}},
+	}
+	rh := newRoute(p, "Default", cfg)
+	for f, fnd := range p.GetFunc("test", "route") {
+		rh.Parse(f, fnd)
+	}
+	if len(rh.Handles) != 1 {
+		t.Fatalf("handle size %d", len(rh.Handles))
+	}
+
+	// wrapper in config, not JSON of gin
+	rh.Handles[0].Parse()
+	want := "// @Success 200 {object} resp.Body{data=User}"
+	for _, s := range rh.Handles[0].Cmt.Decs() {
+		if s == want {
+			return
+		}
+	}
+	t.Fatalf("%v, want %s", rh.Handles[0].Cmt.Decs(), want)
+}
+
+func TestParamLocations(t *testing.T) {
+	code := `
+package test
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func route() {
+	g := gin.Default()
+	g.GET("/item/:id", getItem)
+}
+
+func getItem(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	trace, _ := strconv.ParseBool(c.GetHeader("id"))
+	q := c.Query("id")
+	c.String(200, fmt.Sprint(id, trace, q))
+}
+`
+	f, err := file.New(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := proj.New()
+	p.AddFile(f)
+
+	rh := newRoute(p, "Default", &conf.Config{})
+	for f, fnd := range p.GetFunc("test", "route") {
+		rh.Parse(f, fnd)
+	}
+	if len(rh.Handles) != 1 {
+		t.Fatalf("%d handles, want 1", len(rh.Handles))
+	}
+	hdl := rh.Handles[0]
+	hdl.Parse()
+
+	// type of param is set in its own location
+	var got []string
+	for _, param := range hdl.Cmt.Params() {
+		got = append(got, param.In()+":"+param.Name+":"+param.RefType)
+	}
+	sort.Strings(got)
+	if want := "header:id:boolean path:id:integer query:id:string"; strings.Join(got, " ") != want {
+		t.Fatalf("%v, want %s", got, want)
+	}
+}
+
+func TestBindEmbedded(t *testing.T) {
+	code := `
+package test
+
+import "github.com/gin-gonic/gin"
+
+type Page struct {
+	Page int
+	Size int ` + "`form:\"size\"`" + `
+}
+
+type search struct {
+	*Page
+	Keyword string ` + "`form:\"kw\"`" + `
+	Skip    string ` + "`form:\"-\"`" + `
+}
+
+func route() {
+	g := gin.Default()
+	g.GET("/search", handleSearch)
+}
+
+func handleSearch(c *gin.Context) {
+	var s search
+	_ = c.ShouldBindQuery(&s)
+}
+`
+	f, err := file.New(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := proj.New()
+	p.AddFile(f)
+
+	rh := newRoute(p, "Default", &conf.Config{})
+	for f, fnd := range p.GetFunc("test", "route") {
+		rh.Parse(f, fnd)
+	}
+	if len(rh.Handles) != 1 {
+		t.Fatalf("%d handles, want 1", len(rh.Handles))
+	}
+	hdl := rh.Handles[0]
+	hdl.Parse()
+	var got []string
+	for _, param := range hdl.Cmt.Params() {
+		got = append(got, param.In()+":"+param.Name)
+	}
+	sort.Strings(got)
+	if want := "query:Page query:kw query:size"; strings.Join(got, " ") != want {
+		t.Fatalf("%v, want %s", got, want)
+	}
+}
@@ -1,5 +1,16 @@
 package parser
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dave/dst"
+	"github.com/hocv/gin-swagger-gen/lib/file"
+	"github.com/hocv/gin-swagger-gen/parser/spec"
+	"github.com/pkg/errors"
+)
+
 var (
 	version      = "version"
 	title        = "title"
@@ -9,6 +20,8 @@ var (
 	contactURL   = "contact.url"
 	host         = "host"
 	basePath     = "basepath"
+	licenseName  = "license.name"
+	licenseURL   = "license.url"
 	baseInfo     = []string{
 		title,
 		version,
@@ -20,8 +33,8 @@ var (
 		basePath,
 	}
 	licenseInfo = []string{
-		"license.name",
-		"license.url",
+		licenseName,
+		licenseURL,
 	}
 	tagInfo = []string{
 		"tag.name",
@@ -38,14 +51,313 @@ var (
 		"securitydefinitions.oauth2.password",
 		"securitydefinitions.oauth2.accesscode",
 	}
-	defaultValue = map[string]string{
-		title:        "Swagger Example API",
-		version:      "1.0",
-		description:  "This is a sample server Petstore server.",
-		contactName:  "API Support",
-		contactEmail: "http://www.swagger.io/support",
-		contactURL:   "support@swagger.io",
-		host:         "petstore.swagger.io",
-		basePath:     "/v2",
+	// attrNames name of attribute in comment, same as key if not exists
+	attrNames = map[string]string{
+		basePath:           "BasePath",
+		"termsofservice":   "termsOfService",
+		"tokenurl":         "tokenUrl",
+		"authorizationurl": "authorizationUrl",
 	}
 )
+
+const securityPrefix = "securitydefinitions."
+
+func attrName(key string) string {
+	if name, ok := attrNames[key]; ok {
+		return name
+	}
+	return key
+}
+
+// security security definition. e.g.
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+type security struct {
+	kind  string      // e.g. basic, apikey, oauth2.password
+	name  string      // e.g. ApiKeyAuth
+	attrs [][2]string // e.g. [in header], [scope.read Grants read access]
+}
+
+// parseSecurity parse security definition from string.
+// e.g. "apikey ApiKeyAuth in=header name=Authorization"
+func parseSecurity(def string) (security, error) {
+	fields := strings.Fields(def)
+	if len(fields) < 2 {
+		return security{}, errors.Errorf("invalid security definition: %s", def)
+	}
+	kind := strings.ToLower(fields[0])
+	if !isSecurityKind(kind) {
+		return security{}, errors.Errorf("unknown security type: %s", fields[0])
+	}
+	s := security{kind: kind, name: fields[1]}
+	for _, f := range fields[2:] {
+		kv := strings.SplitN(f, "=", 2)
+		// value with space. e.g. scope.read=Grants read access
+		if len(kv) != 2 {
+			if len(s.attrs) == 0 {
+				return security{}, errors.Errorf("invalid security attribute: %s", f)
+			}
+			s.attrs[len(s.attrs)-1][1] += " " + f
+			continue
+		}
+		s.attrs = append(s.attrs, [2]string{attrName(strings.ToLower(kv[0])), kv[1]})
+	}
+	return s, nil
+}
+
+func isSecurityKind(kind string) bool {
+	for _, s := range securityInfo {
+		if s == securityPrefix+kind {
+			return true
+		}
+	}
+	return false
+}
+
+func isSecurityAttr(key string) bool {
+	switch key {
+	case "description", "in", "name", "tokenurl", "authorizationurl":
+		return true
+	}
+	return strings.HasPrefix(key, "scope.")
+}
+
+func (s security) attr(key string) string {
+	for _, kv := range s.attrs {
+		if strings.ToLower(kv[0]) == key {
+			return kv[1]
+		}
+	}
+	return ""
+}
+
+func (s security) scopes() map[string]string {
+	scopes := map[string]string{}
+	for _, kv := range s.attrs {
+		if strings.HasPrefix(strings.ToLower(kv[0]), "scope.") {
+			scopes[kv[0][len("scope."):]] = kv[1]
+		}
+	}
+	return scopes
+}
+
+func (s security) decs() []string {
+	decs := []string{fmt.Sprintf("// @securityDefinitions.%s %s", s.kind, s.name)}
+	for _, kv := range s.attrs {
+		decs = append(decs, fmt.Sprintf("// @%s %s", kv[0], kv[1]))
+	}
+	return decs
+}
+
+// oauth2 flows of swagger and OpenAPI 3
+var oauth2Flows = map[string][2]string{
+	"oauth2.application": {"application", "clientCredentials"},
+	"oauth2.implicit":    {"implicit", "implicit"},
+	"oauth2.password":    {"password", "password"},
+	"oauth2.accesscode":  {"accessCode", "authorizationCode"},
+}
+
+func (s security) swagger() *spec.SecurityScheme {
+	ss := &spec.SecurityScheme{Description: s.attr("description")}
+	switch s.kind {
+	case "basic":
+		ss.Type = "basic"
+	case "apikey":
+		ss.Type, ss.In, ss.Name = "apiKey", s.attr("in"), s.attr("name")
+	default:
+		ss.Type = "oauth2"
+		ss.Flow = oauth2Flows[s.kind][0]
+		ss.AuthorizationURL = s.attr("authorizationurl")
+		ss.TokenURL = s.attr("tokenurl")
+		ss.Scopes = s.scopes()
+	}
+	return ss
+}
+
+func (s security) openAPI() *spec.OpenAPISecurityScheme {
+	ss := &spec.OpenAPISecurityScheme{Description: s.attr("description")}
+	switch s.kind {
+	case "basic":
+		ss.Type, ss.Scheme = "http", "basic"
+	case "apikey":
+		ss.Type, ss.In, ss.Name = "apiKey", s.attr("in"), s.attr("name")
+	default:
+		ss.Type = "oauth2"
+		ss.Flows = map[string]*spec.OAuthFlow{
+			oauth2Flows[s.kind][1]: {
+				AuthorizationURL: s.attr("authorizationurl"),
+				TokenURL:         s.attr("tokenurl"),
+				Scopes:           s.scopes(),
+			},
+		}
+	}
+	return ss
+}
+
+// generalInfo general api info, in comment of main or function creating engine
+type generalInfo struct {
+	attrs    map[string]string // key: lower case attribute. e.g. title, license.name
+	names    map[string]string // key: lower case attribute, value: name in old comment
+	security []security
+	others   []string // lines not belong to general info, e.g. doc of function, @tag.name
+	file     *file.File
+	decl     *dst.FuncDecl
+	stale    bool // comment in file differs from generated
+}
+
+// newGeneralInfo general info without attributes, only ones in old comment,
+// config and flags are written
+func newGeneralInfo() *generalInfo {
+	return &generalInfo{
+		attrs: map[string]string{},
+		names: map[string]string{},
+	}
+}
+
+// parseComment parse old comment
+func (gi *generalInfo) parseComment(lines []string) {
+	cur := -1 // index of security the line belongs to
+	for _, line := range lines {
+		text := strings.TrimSpace(strings.TrimPrefix(line, "//"))
+		if !strings.HasPrefix(text, "@") {
+			gi.others = append(gi.others, line)
+			continue
+		}
+		attr := strings.Fields(text)[0][1:]
+		value := strings.TrimSpace(text[len(attr)+1:])
+		key := strings.ToLower(attr)
+
+		switch {
+		case strings.HasPrefix(key, securityPrefix):
+			cur = gi.setSecurity(security{kind: key[len(securityPrefix):], name: value})
+		case cur >= 0 && isSecurityAttr(key):
+			gi.security[cur].attrs = append(gi.security[cur].attrs, [2]string{attr, value})
+		case strings.HasPrefix(key, "tag."):
+			cur = -1
+			gi.others = append(gi.others, line)
+		default:
+			cur = -1
+			gi.attrs[key] = value
+			gi.names[key] = attr
+		}
+	}
+}
+
+// set override value of attribute, remove it if value is empty
+func (gi *generalInfo) set(key, value string) {
+	key = strings.ToLower(key)
+	if len(value) == 0 {
+		delete(gi.attrs, key)
+		return
+	}
+	gi.attrs[key] = value
+}
+
+// setSecurity replace security with same name, add if not exists
+func (gi *generalInfo) setSecurity(s security) int {
+	for i, old := range gi.security {
+		if old.name == s.name {
+			gi.security[i] = s
+			return i
+		}
+	}
+	gi.security = append(gi.security, s)
+	return len(gi.security) - 1
+}
+
+func (gi *generalInfo) Decs() []string {
+	decs := append([]string{}, gi.others...)
+
+	keys := append(append([]string{}, baseInfo...), licenseInfo...)
+	known := map[string]struct{}{}
+	for _, k := range keys {
+		known[k] = struct{}{}
+	}
+	var extra []string
+	for k := range gi.attrs {
+		if _, ok := known[k]; !ok {
+			extra = append(extra, k)
+		}
+	}
+	sort.Strings(extra)
+
+	for _, k := range append(keys, extra...) {
+		v, ok := gi.attrs[k]
+		if !ok || len(v) == 0 {
+			continue
+		}
+		name, ok := gi.names[k]
+		if !ok {
+			name = attrName(k)
+		}
+		decs = append(decs, fmt.Sprintf("// @%s %s", name, v))
+	}
+
+	for _, s := range gi.security {
+		decs = append(decs, s.decs()...)
+	}
+	return decs
+}
+
+// Merge merge generated comment to function, false if not changed
+func (gi *generalInfo) Merge() bool {
+	if gi.decl == nil {
+		return false
+	}
+	old := gi.decl.Decs.Start.All()
+	cur := gi.Decs()
+	if len(old) == len(cur) {
+		same := true
+		for i := range old {
+			if old[i] != cur[i] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return false
+		}
+	}
+	gi.decl.Decs.Start.Clear()
+	gi.decl.Decs.Start.Append(cur...)
+	gi.file.Dirty()
+	gi.stale = true
+	return true
+}
+
+func (gi *generalInfo) swaggerInfo() spec.Info {
+	info := spec.Info{
+		Title:       gi.attrs[title],
+		Version:     gi.attrs[version],
+		Description: gi.attrs[description],
+	}
+	if len(gi.attrs[contactName]+gi.attrs[contactEmail]+gi.attrs[contactURL]) > 0 {
+		info.Contact = &spec.Contact{
+			Name:  gi.attrs[contactName],
+			URL:   gi.attrs[contactURL],
+			Email: gi.attrs[contactEmail],
+		}
+	}
+	if len(gi.attrs[licenseName]) > 0 {
+		info.License = &spec.License{
+			Name: gi.attrs[licenseName],
+			URL:  gi.attrs[licenseURL],
+		}
+	}
+	return info
+}
+
+// infoTarget function to write general info, the first one by path of file
+func infoTarget(ffs map[*file.File]*dst.FuncDecl) (*file.File, *dst.FuncDecl, bool) {
+	var target *file.File
+	for f := range ffs {
+		if target == nil || f.Path() < target.Path() {
+			target = f
+		}
+	}
+	if target == nil {
+		return nil, nil, false
+	}
+	return target, ffs[target], true
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGeneralInfo(t *testing.T) {
+	gi := newGeneralInfo()
+	gi.parseComment([]string{
+		"// main start server",
+		"// @title Book API",
+		"// @BasePath /api",
+		"// @securityDefinitions.basic BasicAuth",
+		"// @securityDefinitions.apikey ApiKeyAuth",
+		"// @in header",
+		"// @name Authorization",
+	})
+	gi.set("host", "localhost:8080")
+	gi.set("license.name", "MIT")
+	s, err := parseSecurity("oauth2.password OAuth2 tokenUrl=https://example.com/token scope.read=Grants read access")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gi.setSecurity(s)
+
+	decs := gi.Decs()
+	for _, d := range decs {
+		fmt.Println(d)
+	}
+	want := map[string]bool{
+		"// main start server":                    true,
+		"// @title Book API":                      true,
+		"// @host localhost:8080":                 true,
+		"// @BasePath /api":                       true,
+		"// @license.name MIT":                    true,
+		"// @in header":                           true,
+		"// @tokenUrl https://example.com/token":  true,
+		"// @scope.read Grants read access":       true,
+		"// @securityDefinitions.basic BasicAuth": true,
+	}
+	for _, d := range decs {
+		delete(want, d)
+		// only attributes supplied are written
+		if strings.HasPrefix(d, "// @version") || strings.HasPrefix(d, "// @description") {
+			t.Fatalf("not supplied: %s", d)
+		}
+	}
+	if len(want) > 0 {
+		t.Fatal(want)
+	}
+
+	// generated comment is stable
+	again := newGeneralInfo()
+	again.parseComment(decs)
+	if fmt.Sprint(again.Decs()) != fmt.Sprint(decs) {
+		t.Fatal(again.Decs())
+	}
+
+	sw := (&Parser{info: gi}).Swagger()
+	if sw.Host != "localhost:8080" || sw.BasePath != "/api" || sw.Info.License == nil {
+		t.Fatal(sw.Host, sw.BasePath)
+	}
+	if ss := sw.SecurityDefinitions["ApiKeyAuth"]; ss == nil || ss.Type != "apiKey" || ss.In != "header" {
+		t.Fatal(sw.SecurityDefinitions)
+	}
+	if ss := sw.SecurityDefinitions["OAuth2"]; ss == nil || ss.Flow != "password" || ss.Scopes["read"] != "Grants read access" {
+		t.Fatal(sw.SecurityDefinitions)
+	}
+
+	oa := (&Parser{info: gi}).OpenAPI("3.0")
+	if len(oa.Servers) != 1 || oa.Servers[0].URL != "//localhost:8080/api" {
+		t.Fatal(oa.Servers)
+	}
+	if ss := oa.Components.SecuritySchemes["BasicAuth"]; ss == nil || ss.Type != "http" || ss.Scheme != "basic" {
+		t.Fatal(oa.Components.SecuritySchemes)
+	}
+}
@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hocv/gin-swagger-gen/parser/comment"
+	"github.com/hocv/gin-swagger-gen/parser/spec"
+)
+
+var openAPIVersions = map[string]string{
+	"3":   "3.0.3",
+	"3.0": "3.0.3",
+	"3.1": "3.1.0",
+}
+
+// formMimeTypes content types of formData params
+var formMimeTypes = []string{
+	"multipart/form-data",
+	"application/x-www-form-urlencoded",
+}
+
+// OpenAPI build OpenAPI 3.x document from parsed handles, version: 3.0 or 3.1
+func (parser *Parser) OpenAPI(ver string) *spec.OpenAPI {
+	if v, ok := openAPIVersions[ver]; ok {
+		ver = v
+	}
+	oa := spec.NewOpenAPI(ver)
+	gi := parser.apiInfo()
+	oa.Info = gi.swaggerInfo()
+	if len(gi.attrs[host]+gi.attrs[basePath]) > 0 {
+		url := gi.attrs[basePath]
+		if len(gi.attrs[host]) > 0 {
+			url = "//" + gi.attrs[host] + url
+		}
+		oa.Servers = []spec.Server{{URL: url}}
+	}
+	for _, s := range gi.security {
+		if oa.Components.SecuritySchemes == nil {
+			oa.Components.SecuritySchemes = map[string]*spec.OpenAPISecurityScheme{}
+		}
+		oa.Components.SecuritySchemes[s.name] = s.openAPI()
+	}
+
+	sb := newSchemaBuilder(parser.proj, oa.Components.Schemas, spec.ComponentsRefPrefix)
+	for _, hdl := range parser.hdls {
+		route := hdl.Cmt.Route()
+		op := sb.openAPIOperation(hdl.curPkg, hdl.Cmt, strings.HasPrefix(ver, "3.1"))
+		oa.AddOperation(route.RoutePath, strings.ToLower(route.RouteMethod), op)
+	}
+	return oa
+}
+
+func (sb *schemaBuilder) openAPIOperation(curPkg string, cmt *comment.Comment, v31 bool) *spec.OpenAPIOperation {
+	op := &spec.OpenAPIOperation{
+		Tags:        cmt.Tags(),
+		Summary:     cmt.Summary(),
+		Description: cmt.Description(),
+		OperationID: cmt.ID(),
+		Responses:   map[string]spec.OpenAPIResponse{},
+	}
+
+	consumes := spec.MimeTypes(cmt.Accept())
+	var body *spec.Schema
+	var bodyDesc string
+	form := &spec.Schema{Type: "object"}
+
+	for _, p := range cmt.Params() {
+		s := sb.schema(curPkg, p.RefType)
+		switch p.In() {
+		case "body":
+			body, bodyDesc = s, p.Description
+		case "formData":
+			if s.Type == "file" {
+				s = fileSchema(v31)
+			}
+			setProperty(form, []string{p.Name}, s)
+			if p.Required {
+				form.Required = append(form.Required, p.Name)
+			}
+		default:
+			if s.Type == "" {
+				s = &spec.Schema{Type: "string"}
+			}
+			if len(p.Format) > 0 {
+				s.Format = p.Format
+			}
+			op.Parameters = append(op.Parameters, &spec.OpenAPIParameter{
+				Name:        p.Name,
+				In:          p.In(),
+				Description: p.Description,
+				Required:    p.Required,
+				Schema:      s,
+			})
+		}
+	}
+
+	if body != nil || len(form.Properties) > 0 {
+		op.RequestBody = &spec.RequestBody{
+			Description: bodyDesc,
+			Required:    true,
+			Content:     map[string]spec.MediaType{},
+		}
+		for _, mt := range consumes {
+			if isFormMimeType(mt) {
+				if len(form.Properties) > 0 {
+					op.RequestBody.Content[mt] = spec.MediaType{Schema: form}
+				}
+				continue
+			}
+			if body != nil {
+				op.RequestBody.Content[mt] = spec.MediaType{Schema: body}
+			}
+		}
+		if body != nil && len(op.RequestBody.Content) == 0 {
+			op.RequestBody.Content["application/json"] = spec.MediaType{Schema: body}
+		}
+		if len(form.Properties) > 0 && len(op.RequestBody.Content) == 0 {
+			op.RequestBody.Content[formMimeTypes[0]] = spec.MediaType{Schema: form}
+		}
+	}
+
+	produces := spec.MimeTypes(cmt.Produce())
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+	for _, r := range cmt.Resps() {
+		resp := spec.OpenAPIResponse{
+			Description: http.StatusText(r.Code),
+			Content:     map[string]spec.MediaType{},
+		}
+		s := sb.schema(curPkg, r.Type)
+		if s.Type == "file" {
+			s = fileSchema(v31)
+		}
+		for _, mt := range produces {
+			resp.Content[mt] = spec.MediaType{Schema: s}
+		}
+		op.Responses[strconv.Itoa(r.Code)] = resp
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["default"] = spec.OpenAPIResponse{Description: "default"}
+	}
+	return op
+}
+
+// fileSchema schema of upload file, 3.1 use contentMediaType instead of format binary
+func fileSchema(v31 bool) *spec.Schema {
+	if v31 {
+		return &spec.Schema{Type: "string", ContentMediaType: "application/octet-stream"}
+	}
+	return &spec.Schema{Type: "string", Format: "binary"}
+}
+
+func isFormMimeType(mt string) bool {
+	for _, fm := range formMimeTypes {
+		if fm == mt {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/hocv/gin-swagger-gen/lib/conf"
+	"github.com/hocv/gin-swagger-gen/parser/comment"
+	"github.com/hocv/gin-swagger-gen/parser/spec"
+)
+
+func TestOpenAPI(t *testing.T) {
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ScanDir("./test/main")
+	p.Parse(true)
+
+	oa := p.OpenAPI("3.0")
+	if oa.OpenAPI != "3.0.3" {
+		t.Fatalf("version wrong: %s", oa.OpenAPI)
+	}
+	op, ok := oa.Paths["/g/get"]["get"]
+	if !ok {
+		t.Fatal("operation not found")
+	}
+	if op.RequestBody == nil {
+		t.Fatal("request body not found")
+	}
+	if _, ok := oa.Components.Schemas["main.User"]; !ok {
+		t.Fatal("schema main.User not found")
+	}
+}
+
+func TestOpenAPIFormData(t *testing.T) {
+	cmt := comment.New("upload", "", "/upload", "POST")
+	cmt.AddParam(comment.NewFormDataParam("file", "file", ""))
+	cmt.AddParam(comment.NewFormDataParam("name", "string", ""))
+	cmt.AddAccept("multipart/form-data")
+	cmt.AddProduce("string")
+	cmt.AddResp(comment.Resp{Code: 200, Type: "string"})
+
+	sb := newSchemaBuilder(nil, map[string]*spec.Schema{}, spec.ComponentsRefPrefix)
+	op := sb.openAPIOperation("", cmt, false)
+	if len(op.Parameters) != 0 {
+		t.Fatal("formData should be in request body")
+	}
+	mt, ok := op.RequestBody.Content["multipart/form-data"]
+	if !ok {
+		t.Fatal("multipart/form-data not found")
+	}
+	if mt.Schema.Properties["file"].Format != "binary" {
+		t.Fatal("file should be binary")
+	}
+	if _, ok := op.Responses["200"].Content["text/plain"]; !ok {
+		t.Fatal("text/plain not found")
+	}
+}
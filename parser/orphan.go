@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dave/dst"
+	"github.com/hocv/gin-swagger-gen/lib/conf"
+	"github.com/hocv/gin-swagger-gen/lib/file"
+)
+
+// orphan function with @Router comment, not reached from any route
+type orphan struct {
+	file *file.File
+	decl *dst.FuncDecl
+}
+
+func (o orphan) String() string {
+	return fmt.Sprintf("%s: %s", o.file.Path(), o.decl.Name.Name)
+}
+
+// findOrphans functions with @Router comment which are not handles
+func (parser *Parser) findOrphans() (orphans []orphan) {
+	routed := map[*dst.FuncDecl]bool{}
+	for _, hdl := range parser.hdls {
+		routed[hdl.DstDecl] = true
+	}
+	for _, f := range parser.proj.Files() {
+		if readonly(parser.conf, f) {
+			continue
+		}
+		for _, decl := range f.FuncWithComment("@Router") {
+			if !routed[decl] {
+				orphans = append(orphans, orphan{file: f, decl: decl})
+			}
+		}
+	}
+	return
+}
+
+// strip remove swag lines in comment, @Description is kept as plain text
+func (o orphan) strip() {
+	var decs []string
+	for _, line := range o.decl.Decs.Start.All() {
+		text := strings.TrimSpace(strings.TrimPrefix(line, "//"))
+		if !strings.HasPrefix(text, "@") {
+			decs = append(decs, line)
+			continue
+		}
+		attr := strings.Fields(text)[0]
+		if desc := strings.TrimSpace(text[len(attr):]); strings.EqualFold(attr, "@Description") && len(desc) > 0 {
+			decs = append(decs, "// "+desc)
+		}
+	}
+	o.decl.Decs.Start.Clear()
+	o.decl.Decs.Start.Append(decs...)
+	o.file.Dirty()
+}
+
+// parseOrphans find orphans, strip them if configured and not just print
+func (parser *Parser) parseOrphans(justPrint bool) {
+	// only handle is wanted
+	if len(parser.conf.Func) > 0 || len(parser.conf.Orphans) == 0 {
+		return
+	}
+	parser.orphans = parser.findOrphans()
+	if justPrint || parser.conf.Orphans != conf.OrphansStrip {
+		return
+	}
+	for _, o := range parser.orphans {
+		o.strip()
+	}
+}
+
+// Orphans functions with @Router comment not reached from any route,
+// stripped if orphans is strip in config. e.g. handle.go: oldHandle
+func (parser *Parser) Orphans() []string {
+	var res []string
+	for _, o := range parser.orphans {
+		res = append(res, o.String())
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hocv/gin-swagger-gen/lib/conf"
+	"github.com/hocv/gin-swagger-gen/lib/file"
+)
+
+func TestOrphans(t *testing.T) {
+	code := `
+package test
+
+import "github.com/gin-gonic/gin"
+
+func route() {
+	g := gin.Default()
+	g.GET("/a", handleA)
+}
+
+// @Summary handleA
+// @Router /a [GET]
+func handleA(c *gin.Context) {}
+
+// handleB old handle
+// @Summary handleB
+// @Description get b
+// @Router /b [GET]
+func handleB(c *gin.Context) {}
+`
+	for _, mode := range []string{conf.OrphansReport, conf.OrphansStrip} {
+		p, err := New(&conf.Config{Orphans: mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := file.New(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.proj.AddFile(f)
+		p.Parse(false)
+
+		if len(p.orphans) != 1 || p.orphans[0].decl.Name.Name != "handleB" {
+			t.Fatalf("%s: orphans %v", mode, p.Orphans())
+		}
+		want := []string{"// handleB old handle", "// @Summary handleB", "// @Description get b", "// @Router /b [GET]"}
+		if mode == conf.OrphansStrip {
+			want = []string{"// handleB old handle", "// get b"}
+		}
+		if got := p.orphans[0].decl.Decs.Start.All(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: %v, want %v", mode, got, want)
+		}
+	}
+}
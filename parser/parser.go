@@ -2,39 +2,93 @@ package parser
 
 import (
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 
+	"github.com/dave/dst"
+	"github.com/hocv/gin-swagger-gen/lib/cache"
+	"github.com/hocv/gin-swagger-gen/lib/conf"
+	"github.com/hocv/gin-swagger-gen/lib/file"
 	"github.com/hocv/gin-swagger-gen/lib/pkg"
 	"github.com/hocv/gin-swagger-gen/lib/proj"
+	"github.com/hocv/gin-swagger-gen/lib/typed"
+	"github.com/hocv/gin-swagger-gen/parser/spec"
 	"github.com/pkg/errors"
 )
 
 var ginPkg = "github.com/gin-gonic/gin"
 
 type Parser struct {
-	proj        *proj.Proj
-	specifyFunc string
+	proj       *proj.Proj
+	conf       *conf.Config
+	hdls       []*handle
+	info       *generalInfo
+	securities []security        // security definitions in config
+	cache      *cache.Cache      // nil if cache not used
+	orphans    []orphan          // functions with @Router not routed
+	last       map[string][]byte // generated comments of last Parse, key: path of file and cache key
+	changed    map[string]bool   // packages changed by Reload since last Parse
+	warnings   []string          // routes skipped by last Parse
 }
 
-func New(specifyFunc string) *Parser {
-	return &Parser{
-		specifyFunc: specifyFunc,
-		proj:        proj.New(),
+func New(cfg *conf.Config) (*Parser, error) {
+	parser := &Parser{
+		conf: cfg,
+		proj: proj.New(),
 	}
+	parser.proj.SetWorkers(cfg.Workers)
+	for _, def := range cfg.Security {
+		s, err := parseSecurity(def)
+		if err != nil {
+			return nil, errors.Wrap(err, "new parser")
+		}
+		parser.securities = append(parser.securities, s)
+	}
+	return parser, nil
 }
 
+// ScanDir scan go files in dir, filtered by config
 func (parser *Parser) ScanDir(dir string) {
-	parser.proj.ScanDir(dir)
+	parser.proj.ScanDir(dir, parser.conf.ScanOptions())
+}
+
+// Reload reload changed files in dir, e.g. in watch.
+// next Parse only analyzes handles depending on packages of them
+func (parser *Parser) Reload(dir string, changed []string) {
+	if parser.changed == nil {
+		parser.changed = map[string]bool{}
+	}
+	for _, p := range parser.proj.Reload(dir, parser.conf.ScanOptions(), changed) {
+		parser.changed[p] = true
+	}
+}
+
+// LoadTypes load type info of packages in dir by go/types,
+// used for exact types of bind targets, responses and handlers
+func (parser *Parser) LoadTypes(dir string) error {
+	info, err := typed.Load(dir)
+	if err != nil {
+		return errors.Wrap(err, "load types")
+	}
+	parser.proj.SetTypes(info)
+	return nil
 }
 
 func (parser *Parser) Parse(justPrint bool) {
-	var hdls []*handle
+	parser.hdls, parser.warnings = nil, nil
+	engines := make(map[*file.File]*dst.FuncDecl) // functions creating engine
 	ginFn := func(p *pkg.Pkg, expr string) {
 		fds := p.GetFuncWithSelector(expr)
-		for f, decls := range fds {
-			for _, decl := range decls {
-				rh := newRoute(parser.proj, expr, parser.specifyFunc)
+		for _, f := range p.Files() {
+			for _, decl := range fds[f] {
+				if old, ok := engines[f]; !ok || decl.Name.Name < old.Name.Name {
+					engines[f] = decl
+				}
+				rh := newRoute(parser.proj, expr, parser.conf)
 				rh.Parse(f, decl)
-				hdls = append(hdls, rh.Handles...)
+				parser.hdls = append(parser.hdls, rh.Handles...)
+				parser.warnings = append(parser.warnings, rh.Warnings...)
 			}
 		}
 	}
@@ -49,14 +103,146 @@ func (parser *Parser) Parse(justPrint bool) {
 		ginFn(a, fmt.Sprintf("%s.Default", alias))
 	}
 
-	for _, hdl := range hdls {
-		hdl.Parse()
+	uniqueIDs(parser.hdls)
+	parser.warnings = append(parser.warnings, duplicateRoutes(parser.hdls)...)
+
+	digests := map[string]string{} // key: package
+	for _, hdl := range parser.hdls {
+		parser.analyze(hdl, digests)
+	}
+	parser.keep()
+	// one comment for handle bound to several routes
+	for _, hdls := range groupHandles(parser.hdls) {
 		if justPrint {
-			hdl.Print()
+			printHandles(hdls)
 		} else {
-			hdl.Merge()
+			mergeHandles(hdls)
+		}
+	}
+
+	parser.parseInfo(justPrint, engines)
+	parser.parseOrphans(justPrint)
+}
+
+// parseInfo merge general api info to comment of main,
+// or function creating engine if no main
+func (parser *Parser) parseInfo(justPrint bool, engines map[*file.File]*dst.FuncDecl) {
+	gi := newGeneralInfo()
+	f, decl, ok := infoTarget(parser.proj.GetMainFunc())
+	if !ok {
+		f, decl, ok = infoTarget(engines)
+	}
+	if ok {
+		gi.file, gi.decl = f, decl
+		gi.parseComment(decl.Decs.Start.All())
+	}
+	parser.override(gi)
+	parser.info = gi
+
+	// only handle is wanted
+	if !ok || len(parser.conf.Func) > 0 || readonly(parser.conf, f) {
+		return
+	}
+	if justPrint {
+		for _, s := range gi.Decs() {
+			fmt.Println(s)
+		}
+		return
+	}
+	gi.Merge()
+}
+
+func (parser *Parser) override(gi *generalInfo) {
+	for k, v := range parser.conf.Info {
+		gi.set(k, v)
+	}
+	for _, s := range parser.securities {
+		gi.setSecurity(s)
+	}
+}
+
+// apiInfo general api info, default with overrides if not parsed
+func (parser *Parser) apiInfo() *generalInfo {
+	if parser.info != nil {
+		return parser.info
+	}
+	gi := newGeneralInfo()
+	parser.override(gi)
+	return gi
+}
+
+// Warnings routes skipped by Parse. e.g. methods not accepted by swag
+func (parser *Parser) Warnings() []string {
+	return parser.warnings
+}
+
+// Stale handles whose comment in file differs from generated, and stripped orphans,
+// must be called after Parse without justPrint. e.g. main.go: getHandle
+func (parser *Parser) Stale() []string {
+	var stale []string
+	dic := make(map[string]struct{})
+	for _, hdl := range parser.hdls {
+		if !hdl.stale {
+			continue
+		}
+		s := fmt.Sprintf("%s: %s", hdl.dstFile.Path(), hdl.DstDecl.Name.Name)
+		if _, ok := dic[s]; ok {
+			continue
+		}
+		dic[s] = struct{}{}
+		stale = append(stale, s)
+	}
+	if gi := parser.info; gi != nil && gi.stale {
+		stale = append(stale, fmt.Sprintf("%s: %s", gi.file.Path(), gi.decl.Name.Name))
+	}
+	if parser.conf.Orphans == conf.OrphansStrip {
+		for _, o := range parser.orphans {
+			stale = append(stale, o.String())
 		}
 	}
+	sort.Strings(stale)
+	return stale
+}
+
+// WriteOutputs write documents of outputs in config
+func (parser *Parser) WriteOutputs() error {
+	for _, o := range parser.conf.Outputs {
+		if err := parser.WriteSpec(o.Path, o.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSpec write the document of parsed handles to file,
+// yaml if file ext is .yaml or .yml, otherwise json.
+// version: 2.0 for swagger, 3.0 or 3.1 for OpenAPI 3
+func (parser *Parser) WriteSpec(path, version string) error {
+	var doc interface{} = parser.Swagger()
+	if strings.HasPrefix(version, "3") {
+		doc = parser.OpenAPI(version)
+	}
+	if err := spec.Write(doc, path); err != nil {
+		return errors.Wrap(err, "write spec")
+	}
+	return nil
+}
+
+// Hashes hashes of scanned files, of saved ones after Save. key: path of file
+func (parser *Parser) Hashes() map[string]string {
+	hashes := map[string]string{}
+	for _, f := range parser.proj.Files() {
+		hashes[f.Path()] = f.Hash()
+	}
+	return hashes
+}
+
+// Diff write unified diff of files to be changed, must be called after Parse without justPrint
+func (parser *Parser) Diff(w io.Writer) error {
+	if err := parser.proj.Diff(w); err != nil {
+		return errors.Wrap(err, "gen diff")
+	}
+	return nil
 }
 
 func (parser *Parser) Save() error {
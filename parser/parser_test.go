@@ -1,9 +1,360 @@
 package parser
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hocv/gin-swagger-gen/lib/conf"
+)
 
 func TestParse(t *testing.T) {
-	p := New("")
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	p.ScanDir("./test/main")
 	p.Parse(true)
 }
+
+func TestStale(t *testing.T) {
+	stale := func(cfg *conf.Config) []string {
+		p, err := New(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.ScanDir("./test/main")
+		p.Parse(false)
+		return p.Stale()
+	}
+	// getHandle, main is not commented without general info supplied
+	if s := stale(&conf.Config{}); len(s) != 1 {
+		t.Fatalf("stale size wrong: %v", s)
+	}
+	// and main with info in config
+	if s := stale(&conf.Config{Info: map[string]string{"title": "Book API"}}); len(s) != 2 {
+		t.Fatalf("stale size wrong: %v", s)
+	}
+}
+
+func TestParseWithTypes(t *testing.T) {
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ScanDir("./test/typed")
+	if err := p.LoadTypes("./test/typed"); err != nil {
+		t.Fatal(err)
+	}
+	p.Parse(true)
+
+	bodies := map[string][]string{}
+	for _, hdl := range p.hdls {
+		route := hdl.Cmt.Route()
+		for _, param := range hdl.Cmt.Params() {
+			if param.In() == "body" {
+				bodies[route.RoutePath] = append(bodies[route.RoutePath], param.RefType)
+			}
+		}
+	}
+	want := map[string][]string{
+		"/user":  {"User"},
+		"/order": {"Order"},
+	}
+	if !reflect.DeepEqual(bodies, want) {
+		t.Fatalf("bodies %v, want %v", bodies, want)
+	}
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	run := func() *Parser {
+		p, err := New(&conf.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.UseCache(path)
+		p.ScanDir("./test/main")
+		p.Parse(false)
+		if err := p.SaveCache(); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	first, second := run(), run()
+	if len(first.hdls) != len(second.hdls) {
+		t.Fatalf("handle size %d, want %d", len(second.hdls), len(first.hdls))
+	}
+	want := map[string]string{}
+	for _, hdl := range first.hdls {
+		want[hdl.cacheKey] = strings.Join(hdl.Cmt.Decs(), "\n")
+	}
+	for _, hdl := range second.hdls {
+		if !hdl.cached {
+			t.Fatalf("%s should be restored from cache", hdl.DstDecl.Name.Name)
+		}
+		if got, want := strings.Join(hdl.Cmt.Decs(), "\n"), want[hdl.cacheKey]; got != want {
+			t.Fatalf("%s\nwant\n%s", got, want)
+		}
+	}
+}
+
+func TestCacheVersion(t *testing.T) {
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// test binary has no vcs info, cache of other builds is not used
+	if v := p.cacheVersion(); !strings.Contains(v, executableStamp()) {
+		t.Fatalf("%s has no stamp of executable", v)
+	}
+	executable = filepath.Join(t.TempDir(), "missing")
+	defer func() { executable, _ = os.Executable() }()
+	if p.cacheVersion() == p.cacheVersion() {
+		t.Fatal("unknown executable should not share cache")
+	}
+}
+
+func TestGeneratedFiles(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com\n",
+		"proto/order.pb.go": `// Code generated by protoc-gen-go. DO NOT EDIT.
+
+package proto
+
+type Order struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+`,
+		"api/gen.go": `// Code generated by mockgen. DO NOT EDIT.
+
+package api
+
+import "github.com/gin-gonic/gin"
+
+func Gen(c *gin.Context) {}
+`,
+		"main.go": `
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"example.com/api"
+	"example.com/proto"
+)
+
+func main() {
+	r := gin.Default()
+	r.POST("/orders", create)
+	r.GET("/gen", api.Gen)
+}
+
+func create(c *gin.Context) {
+	var o proto.Order
+	_ = c.ShouldBindJSON(&o)
+	c.JSON(200, o)
+}
+`,
+	}
+	dir := writeTree(t, files)
+
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ScanDir(dir)
+	p.Parse(false)
+
+	// types of generated files are resolved
+	if _, ok := p.Swagger().Definitions["proto.Order"]; !ok {
+		t.Fatal("proto.Order not in definitions")
+	}
+	// routes to generated files are documented, but not commented
+	if _, ok := p.Swagger().Paths["/gen"]; !ok {
+		t.Fatal("/gen not in paths")
+	}
+	for _, s := range p.Stale() {
+		if strings.Contains(s, "gen.go") {
+			t.Fatalf("generated file changed: %s", s)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"go.mod": "module example.com\n",
+		"model/model.go": `
+package model
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+`,
+		"user/user.go": `
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+	"example.com/model"
+)
+
+func Get(c *gin.Context) {
+	c.JSON(200, model.User{})
+}
+`,
+		"shop/shop.go": `
+package shop
+
+import "github.com/gin-gonic/gin"
+
+func List(c *gin.Context) {
+	c.JSON(200, []string{})
+}
+`,
+		"main.go": `
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"example.com/shop"
+	"example.com/user"
+)
+
+func main() {
+	r := gin.Default()
+	r.GET("/user", user.Get)
+	r.GET("/shop", shop.List)
+}
+`,
+	})
+
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ScanDir(dir)
+	p.Parse(false)
+
+	// only handles depending on changed packages are analyzed
+	reload := func(files map[string]string, analyzed string) {
+		var changed []string
+		for name, code := range files {
+			path := filepath.Join(dir, name)
+			if len(code) == 0 {
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := ioutil.WriteFile(path, []byte(code), 0666); err != nil {
+				t.Fatal(err)
+			}
+			changed = append(changed, path)
+		}
+		p.Reload(dir, changed)
+		p.Parse(false)
+		for _, hdl := range p.hdls {
+			if name := hdl.DstDecl.Name.Name; hdl.cached == (name == analyzed) {
+				t.Fatalf("%s: cached %v", name, hdl.cached)
+			}
+		}
+	}
+
+	reload(map[string]string{"model/model.go": `
+package model
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+	Age  int    ` + "`json:\"age\"`" + `
+}
+`}, "Get")
+	if _, ok := p.Swagger().Definitions["model.User"].Properties["age"]; !ok {
+		t.Fatal("age not in model.User")
+	}
+
+	reload(map[string]string{
+		"shop/shop.go": "",
+		"shop/list.go": `
+package shop
+
+import "github.com/gin-gonic/gin"
+
+func List(c *gin.Context) {
+	c.JSON(201, []string{})
+}
+`}, "List")
+	for _, s := range p.Stale() {
+		if strings.Contains(s, "shop.go") {
+			t.Fatalf("removed file: %s", s)
+		}
+	}
+	if _, ok := p.Swagger().Paths["/shop"]["get"].Responses["201"]; !ok {
+		t.Fatal("201 not in responses of /shop")
+	}
+}
+
+// writeTree write files of module to a temp dir and return it, key: path relative to the dir
+func writeTree(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, code := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(code), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReloadWithTypes(t *testing.T) {
+	src, err := ioutil.ReadFile("./test/typed/typed.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := ioutil.ReadFile("../go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := writeTree(t, map[string]string{
+		"go.mod":   "module example.com\n\ngo 1.22\n\nrequire github.com/gin-gonic/gin v1.6.3\n",
+		"go.sum":   string(sum),
+		"typed.go": string(src),
+		"other.go": "package typed\n",
+	})
+
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ScanDir(dir)
+	// a watch cycle, saved files are type checked next cycle
+	cycle := func(changed ...string) []string {
+		if len(changed) > 0 {
+			p.Reload(dir, changed)
+		}
+		if err := p.LoadTypes(dir); err != nil {
+			t.Fatal(err)
+		}
+		p.Parse(false)
+		stale := p.Stale()
+		if err := p.Save(); err != nil {
+			t.Fatal(err)
+		}
+		return stale
+	}
+
+	if stale := cycle(); len(stale) == 0 {
+		t.Fatal("handles should be commented")
+	}
+	other := filepath.Join(dir, "other.go")
+	if err := ioutil.WriteFile(other, []byte("package typed\n\nvar Other = 1\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if stale := cycle(other); len(stale) > 0 {
+		t.Fatalf("comments flip: %v", stale)
+	}
+}
@@ -2,11 +2,17 @@ package parser
 
 import (
 	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/hocv/gin-swagger-gen/parser/comment"
 
 	"github.com/dave/dst"
+	"github.com/dave/dst/dstutil"
 	"github.com/hocv/gin-swagger-gen/lib/common"
+	"github.com/hocv/gin-swagger-gen/lib/conf"
 	"github.com/hocv/gin-swagger-gen/lib/file"
 	"github.com/hocv/gin-swagger-gen/lib/proj"
 )
@@ -21,6 +27,25 @@ var routeParsers = map[string]routeParser{
 	"OPTIONS": parseRouteMethod,
 	"HEAD":    parseRouteMethod,
 	"Any":     parseRouteMethod,
+	"Handle":  parseRouteHandle,
+	"Match":   parseRouteMatch,
+
+	"Static":       parseRouteStatic,
+	"StaticFS":     parseRouteStatic,
+	"StaticFile":   parseRouteStatic,
+	"StaticFileFS": parseRouteStatic,
+}
+
+// staticTag default tag of static file routes
+const staticTag = "static"
+
+// anyMethods methods registered by Any and accepted by swag,
+// CONNECT and TRACE of gin are not documented
+var anyMethods = []string{"GET", "POST", "PUT", "PATCH", "HEAD", "OPTIONS", "DELETE"}
+
+// swagMethods methods accepted by @Router of swag and swagger 2.0
+var swagMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "HEAD": true, "OPTIONS": true, "DELETE": true,
 }
 
 // routeFunc handle function
@@ -37,84 +62,426 @@ func parseRouteGroup(rh *route, val string, cal string, call *dst.CallExpr) {
 		return
 	}
 	// "/api",fist arg of function is route path
-	path := call.Args[0].(*dst.BasicLit).Value
-	rh.RouteMap[val] = routeBase + fmtRoutePath(path)
+	path, ok := call.Args[0].(*dst.BasicLit)
+	if !ok {
+		return
+	}
+	rh.RouteMap[val] = routeBase + fmtRoutePath(path.Value)
 }
 
-// parseRouteMethod g.GET("/usr",handelFunc)
+// parseRouteMethod g.GET("/usr",handelFunc), Any for all methods
 func parseRouteMethod(rh *route, _ string, cal string, call *dst.CallExpr) {
-	if len(call.Args) < 2 {
+	sel, ok := call.Fun.(*dst.SelectorExpr)
+	if !ok {
+		return
+	}
+	methods := []string{sel.Sel.Name}
+	if sel.Sel.Name == "Any" {
+		methods = anyMethods
+	}
+	rh.addRoutes(cal, methods, call.Args)
+}
+
+// parseRouteHandle g.Handle("PROPFIND", "/usr", handelFunc)
+func parseRouteHandle(rh *route, _ string, cal string, call *dst.CallExpr) {
+	if len(call.Args) < 3 {
+		return
+	}
+	method, ok := routeMethod(call.Args[0])
+	if !ok {
+		return
+	}
+	rh.addRoutes(cal, []string{method}, call.Args[1:])
+}
+
+// parseRouteMatch g.Match([]string{"GET", http.MethodPost}, "/usr", handelFunc)
+func parseRouteMatch(rh *route, _ string, cal string, call *dst.CallExpr) {
+	if len(call.Args) < 3 {
+		return
+	}
+	lit, ok := call.Args[0].(*dst.CompositeLit)
+	if !ok {
+		return
+	}
+	var methods []string
+	for _, elt := range lit.Elts {
+		if method, ok := routeMethod(elt); ok {
+			methods = append(methods, method)
+		}
+	}
+	rh.addRoutes(cal, methods, call.Args[1:])
+}
+
+// parseRouteStatic g.Static("/assets", "./assets"), g.StaticFile("/favicon.ico", "./favicon.ico"),
+// GET and HEAD operations in document, no function to comment
+func parseRouteStatic(rh *route, _ string, cal string, call *dst.CallExpr) {
+	if len(call.Args) < 2 || len(rh.conf.Func) > 0 {
 		return
 	}
 	routeBase, ok := rh.RouteMap[cal]
 	if !ok {
 		return
 	}
+	lit, ok := call.Args[0].(*dst.BasicLit)
+	if !ok {
+		return
+	}
+	sel, ok := call.Fun.(*dst.SelectorExpr)
+	if !ok {
+		return
+	}
 
-	// first arg of function is route path
-	firstArg := call.Args[0].(*dst.BasicLit).Value
-	// just use last handle function, middle functions maybe middleware
-	lastArg := common.ToStr(call.Args[len(call.Args)-1])
-	handleCall, handleFn := splitDot(lastArg)
-	v, ok := rh.Vars[handleCall]
-	if ok {
-		handleCall = v
+	// files in dir. e.g. /assets/{filepath}
+	dir := sel.Sel.Name == "Static" || sel.Sel.Name == "StaticFS"
+	routePath := routeBase + fmtRoutePath(lit.Value)
+	if dir {
+		routePath = strings.TrimSuffix(routePath, "/") + "/{filepath}"
+	}
+	root := common.BasicLitValue(call.Args[1])
+	if len(root) == 0 {
+		root = common.ToStr(call.Args[1])
+	}
+	tag := rh.conf.StaticTag
+	if len(tag) == 0 {
+		tag = staticTag
 	}
 
-	// if specify the function, ignore others
-	if len(rh.specifyFunc) > 0 && rh.specifyFunc != handleFn {
+	for _, method := range []string{"GET", "HEAD"} {
+		cmt := comment.New(fmt.Sprintf("%s %s", sel.Sel.Name, root), routeBase, routePath, method)
+		cmt.SetTags(tag)
+		if dir {
+			cmt.AddParam(comment.NewPathParam("filepath", "string", wildcardDesc))
+		}
+		cmt.AddProduce("octet-stream")
+		cmt.AddResp(comment.Resp{Code: 200, Type: "file"})
+		rh.Handles = append(rh.Handles, newHandle(rh.proj, rh.conf, rh.file, nil, nil, cmt))
+	}
+}
+
+// routeMethod method in string or constant of net/http. e.g. "PROPFIND", http.MethodGet
+func routeMethod(expr dst.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *dst.BasicLit:
+		method, err := strconv.Unquote(e.Value)
+		if err != nil || len(method) == 0 {
+			return "", false
+		}
+		return strings.ToUpper(method), true
+	case *dst.SelectorExpr:
+		if !strings.HasPrefix(e.Sel.Name, "Method") || len(e.Sel.Name) == len("Method") {
+			return "", false
+		}
+		return strings.ToUpper(strings.TrimPrefix(e.Sel.Name, "Method")), true
+	}
+	return "", false
+}
+
+// addRoutes add a handle for each method, args: path, handle functions
+func (rh *route) addRoutes(cal string, methods []string, args []dst.Expr) {
+	if len(args) < 2 {
+		return
+	}
+	routeBase, ok := rh.RouteMap[cal]
+	if !ok {
 		return
 	}
 
-	sel, ok := call.Fun.(*dst.SelectorExpr)
+	// first arg of function is route path
+	lit, ok := args[0].(*dst.BasicLit)
 	if !ok {
 		return
 	}
+	firstArg := lit.Value
+	// just use last handle function, middle functions maybe middleware
+	lastArg := args[len(args)-1]
+	handleFn := handleName(lastArg)
 
-	routePath := routeBase + fmtRoutePath(firstArg)
+	// if specify the function, ignore others
+	if len(rh.conf.Func) > 0 && rh.conf.Func != handleFn {
+		return
+	}
 
-	cmt := comment.New(handleFn, routeBase, routePath, sel.Sel.Name)
+	routePath := routeBase + fmtRoutePath(firstArg)
+	wildcard := routeWildcard(firstArg)
 	pps := routePathParams(routePath)
-	for _, p := range pps {
-		if len(p) > 0 {
-			cmt.AddParam(comment.NewPathParam(p, "string", p))
+
+	// other methods are skipped, e.g. Handle("PROPFIND", ...)
+	var supported []string
+	for _, method := range methods {
+		if swagMethods[method] {
+			supported = append(supported, method)
+			continue
+		}
+		rh.Warnings = append(rh.Warnings, fmt.Sprintf("%s: %s %s: method not accepted by swag, skipped", rh.file.Path(), method, routePath))
+	}
+	methods = supported
+
+	var ffs map[*file.File]*dst.FuncDecl
+	fnLit, isLit := lastArg.(*dst.FuncLit)
+	if isLit {
+		// inline handle has nothing to comment, only in document
+		ffs = map[*file.File]*dst.FuncDecl{rh.file: nil}
+	} else {
+		ffs = rh.handleOf(lastArg)
+	}
+	_, isFactory := lastArg.(*dst.CallExpr)
+	for _, method := range methods {
+		for f, fnd := range ffs {
+			var src *dst.FuncDecl
+			switch {
+			case isLit:
+				src = &dst.FuncDecl{Name: dst.NewIdent(""), Type: fnLit.Type, Body: fnLit.Body}
+			case isFactory:
+				// handle is returned by factory, comment is written on factory
+				if src = factoryHandle(fnd); src == nil {
+					continue
+				}
+			}
+			summary := handleFn
+			if isLit {
+				// no function name. e.g. GET /ping
+				summary = method + " " + routePath
+			}
+			cmt := comment.New(summary, routeBase, routePath, method)
+			for _, p := range pps {
+				if len(p) == 0 {
+					continue
+				}
+				desc := p
+				if p == wildcard {
+					desc = wildcardDesc
+				}
+				cmt.AddParam(comment.NewPathParam(p, "string", desc))
+			}
+			cmt.SetSuccess(rh.conf.Success)
+			cmt.SetTags(routeTags(rh.conf.Tag, routeBase, routePath, f.Pkg()))
+			fh := newHandle(rh.proj, rh.conf, f, fnd, src, cmt)
+			if isLit {
+				cmt.SetID(operationID(method, routePath))
+				// vars of route function captured by inline handle
+				for k, v := range rh.Vars {
+					if _, ok := fh.Vars[k]; !ok {
+						fh.Vars[k] = v
+					}
+				}
+			}
+			rh.Handles = append(rh.Handles, fh)
+		}
+	}
+}
+
+// operationID id of operation without function. e.g. GET /users/{id}/books -> getUsersIdBooks
+func operationID(method, routePath string) string {
+	id := strings.ToLower(method)
+	for _, seg := range strings.Split(routePath, "/") {
+		seg = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, seg)
+		if len(seg) > 0 {
+			id += strings.ToUpper(seg[:1]) + seg[1:]
+		}
+	}
+	return id
+}
+
+// uniqueIDs suffix generated operation ids of inline handles which are same for other routes.
+// e.g. GET /users/:id and GET /users/id -> getUsersId, getUsersId2
+func uniqueIDs(hdls []*handle) {
+	routes := make(map[string]string) // key: id, value: route
+	for _, hdl := range hdls {
+		id := hdl.Cmt.ID()
+		if hdl.DstDecl != nil || len(id) == 0 {
+			continue
+		}
+		route := hdl.Cmt.Route()
+		key := route.RouteMethod + " " + route.RoutePath
+		uid := id
+		for i := 2; ; i++ {
+			if r, ok := routes[uid]; !ok || r == key {
+				break
+			}
+			uid = id + strconv.Itoa(i)
+		}
+		routes[uid] = key
+		hdl.Cmt.SetID(uid)
+	}
+}
+
+// duplicateRoutes warnings of handles registered to route of another one, only first is documented
+func duplicateRoutes(hdls []*handle) []string {
+	var warnings []string
+	routes := make(map[string]bool) // key: method and path
+	for _, hdl := range hdls {
+		route := hdl.Cmt.Route()
+		key := route.RouteMethod + " " + route.RoutePath
+		if routes[key] {
+			warnings = append(warnings, fmt.Sprintf("%s: %s: duplicate route, only first one is documented", hdl.dstFile.Path(), key))
+			continue
+		}
+		routes[key] = true
+	}
+	return warnings
+}
+
+// handleName name of handle function. e.g. ctrl.List -> List, handlers.CreateOrder(svc) -> CreateOrder
+func handleName(expr dst.Expr) string {
+	if call, ok := expr.(*dst.CallExpr); ok {
+		expr = call.Fun
+	}
+	_, name := splitDot(common.ToStr(expr))
+	return name
+}
+
+// factoryHandle handle function returned by factory, nil if decl is not a factory.
+// params of factory are kept, so captured vars can be analyzed. e.g.
+// func CreateOrder(svc *Service) gin.HandlerFunc { return func(c *gin.Context) {...} }
+func factoryHandle(decl *dst.FuncDecl) *dst.FuncDecl {
+	results := decl.Type.Results
+	if decl.Body == nil || results == nil || len(results.List) != 1 || !isHandlerFunc(results.List[0].Type) {
+		return nil
+	}
+	var lit *dst.FuncLit
+	dst.Inspect(decl.Body, func(node dst.Node) bool {
+		if lit != nil {
+			return false
+		}
+		ret, ok := node.(*dst.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			return true
+		}
+		switch r := ret.Results[0].(type) {
+		case *dst.FuncLit:
+			lit = r
+		case *dst.CallExpr:
+			// conversion. e.g. gin.HandlerFunc(func(c *gin.Context) {...})
+			if len(r.Args) == 1 && isHandlerFunc(r.Fun) {
+				lit, _ = r.Args[0].(*dst.FuncLit)
+			}
 		}
+		return true
+	})
+	if lit == nil {
+		return nil
 	}
+	params := append([]*dst.Field{}, decl.Type.Params.List...)
+	params = append(params, lit.Type.Params.List...)
+	return &dst.FuncDecl{
+		Recv: decl.Recv,
+		Name: decl.Name,
+		Type: &dst.FuncType{Func: true, Params: &dst.FieldList{List: params}},
+		Body: lit.Body,
+	}
+}
+
+// isHandlerFunc whether type is gin.HandlerFunc or func(*gin.Context)
+func isHandlerFunc(expr dst.Expr) bool {
+	ft, ok := expr.(*dst.FuncType)
+	if !ok {
+		return strings.HasSuffix(common.ToStr(expr), ".HandlerFunc")
+	}
+	params := ft.Params.List
+	return (ft.Results == nil || len(ft.Results.List) == 0) && len(params) == 1 && len(params[0].Names) <= 1 &&
+		strings.HasSuffix(common.ToStr(params[0].Type), ".Context")
+}
+
+// handleOf function of handle expr. e.g. handle, api.Handle, ctrl.List, h.User.List, controller.NewUser(svc).List
+// for factory call, it is the factory. e.g. handlers.CreateOrder(svc)
+func (rh *route) handleOf(expr dst.Expr) map[*file.File]*dst.FuncDecl {
+	if call, ok := expr.(*dst.CallExpr); ok {
+		return rh.handleOf(call.Fun)
+	}
+	if ffs, ok := rh.proj.FuncOf(rh.file, expr); ok {
+		return ffs
+	}
+	sel, ok := expr.(*dst.SelectorExpr)
+	if !ok {
+		return rh.proj.GetFuncIn(rh.file, common.ToStr(expr))
+	}
+	// method by type of receiver
+	if curPkg, recv, ok := rh.recvType(sel.X); ok {
+		if ffs := rh.proj.GetMethod(curPkg, recv, sel.Sel.Name); len(ffs) > 0 {
+			return ffs
+		}
+	}
+	// function in package imported by file
+	if ffs := rh.proj.GetFuncIn(rh.file, common.ToStr(expr)); len(ffs) > 0 {
+		return ffs
+	}
+	return rh.proj.GetFunc(rh.curPkg, sel.Sel.Name)
+}
+
+// recvType type of receiver expr, and package the type is used in
+func (rh *route) recvType(expr dst.Expr) (string, string, bool) {
+	switch e := expr.(type) {
+	case *dst.Ident:
+		t, ok := rh.Vars[e.Name]
+		return rh.curPkg, t, ok
+	case *dst.SelectorExpr:
+		// field. e.g. h.User
+		curPkg, t, ok := rh.recvType(e.X)
+		if !ok {
+			return "", "", false
+		}
+		return rh.proj.FieldType(curPkg, t, e.Sel.Name)
+	case *dst.CallExpr:
+		// constructor. e.g. controller.NewUser(svc)
+		if rs := rh.proj.CallResult(rh.curPkg, e, rh.Vars); len(rs) > 0 {
+			return rh.curPkg, rs[0], true
+		}
+	case *dst.ParenExpr:
+		return rh.recvType(e.X)
+	}
+	return "", "", false
+}
 
-	ffs := rh.proj.GetFunc(rh.curPkg, handleFn)
-	for f, fnd := range ffs {
-		fh := newHandle(rh.proj, f, fnd, nil, cmt)
-		rh.Handles = append(rh.Handles, fh)
+// routeTags tags of handle by strategy
+func routeTags(strategy, routeBase, routePath, pkgName string) string {
+	switch strategy {
+	case conf.TagPackage:
+		return pkgName
+	case conf.TagPath:
+		return strings.Split(strings.TrimPrefix(routePath, "/"), "/")[0]
+	case conf.TagNone:
+		return ""
 	}
+	return strings.TrimPrefix(routeBase, "/")
 }
 
 type route struct {
-	proj        *proj.Proj
-	engineVar   string
-	initExpr    string
-	curPkg      string
-	specifyFunc string
-	Vars        map[string]string
-	RouteMap    map[string]string
-	Handles     []*handle
+	proj      *proj.Proj
+	engineVar string
+	initExpr  string
+	file      *file.File
+	curPkg    string
+	conf      *conf.Config
+	Vars      map[string]string
+	RouteMap  map[string]string
+	Handles   []*handle
+	Warnings  []string                     // routes skipped
+	tables    map[string]*dst.CompositeLit // local vars of composite literal, maybe route tables
 }
 
-func newRoute(proj *proj.Proj, initExpr, specifyFunc string) *route {
+func newRoute(proj *proj.Proj, initExpr string, cfg *conf.Config) *route {
 	// split g := gin.New() or g := gin.Default()
 	_, ginFunc := splitDot(initExpr)
 	return &route{
-		proj:        proj,
-		initExpr:    ginFunc,
-		specifyFunc: specifyFunc,
-		Vars:        make(map[string]string),
-		RouteMap:    map[string]string{},
+		proj:     proj,
+		initExpr: ginFunc,
+		conf:     cfg,
+		Vars:     make(map[string]string),
+		RouteMap: map[string]string{},
+		tables:   map[string]*dst.CompositeLit{},
 	}
 }
 
 func (rh *route) Parse(f *file.File, fnd *dst.FuncDecl) {
-	rh.curPkg = f.Pkg()
+	rh.file = f
+	rh.curPkg = f.PkgPath()
 	// global vars
-	for k, v := range rh.proj.GetGlobalVar(f.Pkg()) {
+	for k, v := range rh.proj.GetGlobalVar(f.PkgPath()) {
 		rh.Vars[k] = v
 	}
 	// vars in function param
@@ -126,25 +493,88 @@ func (rh *route) Parse(f *file.File, fnd *dst.FuncDecl) {
 }
 
 func (rh *route) Copy(vars map[string]string) *route {
-	rm := copyMap(rh.RouteMap)
+	nrh := &route{
+		proj:     rh.proj,
+		initExpr: rh.initExpr,
+		conf:     rh.conf,
+		Vars:     make(map[string]string),
+		RouteMap: copyMap(rh.RouteMap),
+		tables:   map[string]*dst.CompositeLit{},
+	}
+	// params replace route vars passed in, names maybe same. e.g. func setRoute(r *gin.Engine)
+	for _, v := range vars {
+		delete(nrh.RouteMap, v)
+	}
 	for k, v := range vars {
 		if vv, ok := rh.RouteMap[v]; ok {
-			rm[k] = vv
-			delete(rm, v)
+			nrh.RouteMap[k] = vv
+		}
+		if v == rh.engineVar {
+			nrh.engineVar = k
 		}
 	}
+	return nrh
+}
 
-	nrh := &route{
-		proj:        rh.proj,
-		initExpr:    rh.initExpr,
-		specifyFunc: rh.specifyFunc,
-		Vars:        make(map[string]string),
-		RouteMap:    rm,
+// routeArgs args of call, and route path of groups created in args. false if no route var or group in args.
+// e.g. v1.Routes(r.Group("/v1")) -> ["r.Group"], {0: "/v1"}
+func (rh *route) routeArgs(call *dst.CallExpr) ([]string, map[int]string, bool) {
+	var ps []string
+	groups := make(map[int]string)
+	ok := false
+	for idx, arg := range call.Args {
+		str := common.ToStr(arg)
+		ps = append(ps, str)
+		if _, has := rh.RouteMap[str]; has {
+			ok = true
+			continue
+		}
+		pc, isCall := arg.(*dst.CallExpr)
+		cal, sel := splitDot(str)
+		if !isCall || sel != "Group" || len(pc.Args) == 0 {
+			continue
+		}
+		routeBase, has := rh.RouteMap[cal]
+		if !has {
+			continue
+		}
+		path, isLit := pc.Args[0].(*dst.BasicLit)
+		if !isLit {
+			continue
+		}
+		groups[idx] = routeBase + fmtRoutePath(path.Value)
+		ok = true
 	}
-	return nrh
+	return ps, groups, ok
 }
 
 func (rh *route) parseItem(stmt interface{}, vars map[string]string) {
+	switch s := stmt.(type) {
+	case *dst.RangeStmt:
+		rh.parseRange(s, vars)
+		return
+	case *dst.AssignStmt:
+		// e.g. routes := []Route{...}
+		if len(s.Lhs) == len(s.Rhs) {
+			for i, l := range s.Lhs {
+				rh.addTable(l, s.Rhs[i])
+			}
+		}
+	case *dst.DeclStmt:
+		// e.g. var routes = []Route{...}
+		if gd, ok := s.Decl.(*dst.GenDecl); ok {
+			for _, spec := range gd.Specs {
+				vs, ok := spec.(*dst.ValueSpec)
+				if !ok || len(vs.Names) != len(vs.Values) {
+					continue
+				}
+				for i, name := range vs.Names {
+					rh.addTable(name, vs.Values[i])
+				}
+			}
+		}
+	}
+
 	vs := rh.proj.GetVarsFromStmt(stmt, rh.curPkg, vars)
 	for v, t := range vs {
 		_, sel := splitDot(t)
@@ -172,72 +602,204 @@ func (rh *route) parseItem(stmt interface{}, vars map[string]string) {
 			continue
 		}
 
-		// recursive
+		// recursive, routes set up in function with route vars or groups.
+		// e.g. setRoute(g), api.Register(r), v1.Routes(r.Group("/v1"))
 		call, err := common.GetCallExprByVarName(stmt, v)
 		if err != nil {
 			continue
 		}
-
-		var innerRoutePath string
-		var innerRouteIdx int
-		ps, ok := common.CheckCallExprParam(call, rh.engineVar)
+		ps, groups, ok := rh.routeArgs(call)
 		if !ok {
-			if len(ps) == 0 || ps[0] == rh.engineVar {
-				continue
-			}
-			groupStr := fmt.Sprintf("%s.Group", rh.engineVar)
-			if groupStr != ps[0] {
-				continue
-			}
-			for idx, arg := range call.Args {
-				switch arg.(type) {
-				case *dst.CallExpr:
-					pc := arg.(*dst.CallExpr)
-					argStr := common.ToStr(arg)
-					if argStr != groupStr {
-						continue
-					}
-					cal, _ := splitDot(argStr)
-					routeBase, ok := rh.RouteMap[cal]
-					if !ok {
-						continue
-					}
-					path := pc.Args[0].(*dst.BasicLit).Value
-					innerRoutePath = routeBase + fmtRoutePath(path)
-					innerRouteIdx = idx
-					break
-				}
-			}
+			continue
 		}
 
-		ffs := rh.proj.GetFunc(rh.curPkg, t)
-		if len(ffs) == 0 {
-			continue
+		ffs := rh.proj.GetFuncIn(rh.file, t)
+		if sel, ok := call.Fun.(*dst.SelectorExpr); ok && len(ffs) == 0 {
+			// method by type of receiver. e.g. h.Register(r)
+			if curPkg, recv, ok := rh.recvType(sel.X); ok {
+				ffs = rh.proj.GetMethod(curPkg, recv, sel.Sel.Name)
+			}
 		}
 
 		for f, fnd := range ffs {
+			fps := common.GetFuncParamList(fnd)
+			if len(fps) != len(ps) {
+				continue
+			}
 			nvs := make(map[string]string)
-			innerRouteVal := ""
-			if len(ps) > 0 {
-				fps := common.GetFuncParamList(fnd)
-				if len(fps) != len(ps) {
-					continue
-				}
-				for i, s := range fps {
-					if len(innerRoutePath) > 0 && i == innerRouteIdx {
-						innerRouteVal = s
-						continue
-					}
+			for i, s := range fps {
+				if _, ok := groups[i]; !ok {
 					nvs[s] = ps[i]
 				}
 			}
 
 			nrh := rh.Copy(nvs)
-			if len(innerRouteVal) > 0 {
-				nrh.RouteMap[innerRouteVal] = innerRoutePath
+			for i, path := range groups {
+				nrh.RouteMap[fps[i]] = path
 			}
 			nrh.Parse(f, fnd)
 			rh.Handles = append(rh.Handles, nrh.Handles...)
+			rh.Warnings = append(rh.Warnings, nrh.Warnings...)
+		}
+	}
+}
+
+// addTable record local var of composite literal
+func (rh *route) addTable(name, value dst.Expr) {
+	ident, ok := name.(*dst.Ident)
+	if !ok {
+		return
+	}
+	if lit, ok := value.(*dst.CompositeLit); ok {
+		rh.tables[ident.Name] = lit
+	} else {
+		delete(rh.tables, ident.Name)
+	}
+}
+
+// parseRange routes of loop over route table, body is parsed with range vars replaced by each element. e.g.
+// for _, rt := range []Route{{"GET", "/users", listUsers}} { g.Handle(rt.Method, rt.Path, rt.Handler) }
+// for i := range routes { g.Handle(routes[i].Method, routes[i].Path, routes[i].Handler) }
+// for path, h := range map[string]gin.HandlerFunc{"/users": listUsers} { g.GET(path, h) }
+// three-clause for loops are not evaluated. e.g. for i := 0; i < len(routes); i++
+func (rh *route) parseRange(rs *dst.RangeStmt, vars map[string]string) {
+	table, tablePkg, qualifier, ok := rh.tableOf(rs.X)
+	if !ok {
+		return
+	}
+	var elemType dst.Expr
+	switch t := table.Type.(type) {
+	case *dst.ArrayType:
+		elemType = t.Elt
+	case *dst.MapType:
+		elemType = t.Value
+	}
+	fieldNames := rh.structFields(tablePkg, elemType)
+
+	for idx, elt := range table.Elts {
+		key, value := dst.Expr(&dst.BasicLit{Kind: token.INT, Value: strconv.Itoa(idx)}), elt
+		if kv, ok := elt.(*dst.KeyValueExpr); ok {
+			key, value = kv.Key, kv.Value
+		}
+		if u, ok := value.(*dst.UnaryExpr); ok && u.Op == token.AND {
+			value = u.X
 		}
+		rangeVars := map[string]dst.Expr{}
+		keyVar, valueVar := common.ToStr(rs.Key), common.ToStr(rs.Value)
+		if len(keyVar) > 0 && keyVar != "_" {
+			rangeVars[keyVar] = rh.qualify(key, tablePkg, qualifier)
+		}
+		if len(valueVar) > 0 && valueVar != "_" {
+			rangeVars[valueVar] = rh.qualify(value, tablePkg, qualifier)
+		}
+		// element by value var or index of table. e.g. rt, routes[i]
+		isElem := func(expr dst.Expr) bool {
+			switch e := expr.(type) {
+			case *dst.Ident:
+				return len(valueVar) > 0 && valueVar != "_" && e.Name == valueVar
+			case *dst.IndexExpr:
+				return len(keyVar) > 0 && keyVar != "_" &&
+					common.ToStr(e.Index) == keyVar && common.ToStr(e.X) == common.ToStr(rs.X)
+			}
+			return false
+		}
+		elem := rh.qualify(value, tablePkg, qualifier)
+
+		// fields of struct element, by key or position
+		fields := map[string]dst.Expr{}
+		if lit, ok := value.(*dst.CompositeLit); ok {
+			for i, e := range lit.Elts {
+				if kv, ok := e.(*dst.KeyValueExpr); ok {
+					fields[common.ToStr(kv.Key)] = rh.qualify(kv.Value, tablePkg, qualifier)
+				} else if i < len(fieldNames) {
+					fields[fieldNames[i]] = rh.qualify(e, tablePkg, qualifier)
+				}
+			}
+		}
+
+		body := dst.Clone(rs.Body).(*dst.BlockStmt)
+		dstutil.Apply(body, func(c *dstutil.Cursor) bool {
+			switch n := c.Node().(type) {
+			case *dst.SelectorExpr:
+				if !isElem(n.X) {
+					return true
+				}
+				if f, ok := fields[n.Sel.Name]; ok {
+					c.Replace(dst.Clone(f))
+					return false
+				}
+			case *dst.IndexExpr:
+				if isElem(n) {
+					c.Replace(dst.Clone(elem))
+					return false
+				}
+			case *dst.Ident:
+				// field name. e.g. x.rt, Route{rt: ...}
+				if c.Name() == "Sel" || c.Name() == "Key" {
+					return true
+				}
+				if v, ok := rangeVars[n.Name]; ok {
+					c.Replace(dst.Clone(v))
+					return false
+				}
+			}
+			return true
+		}, nil)
+		parseStmtList(body.List, copyMap(vars), rh.parseItem)
+	}
+}
+
+// tableOf composite literal of route table, package it is declared in, and qualifier of the package if not current one.
+// e.g. []Route{...}, routes, api.Routes
+func (rh *route) tableOf(expr dst.Expr) (*dst.CompositeLit, string, string, bool) {
+	var value dst.Expr
+	switch e := expr.(type) {
+	case *dst.CompositeLit:
+		return e, rh.curPkg, "", true
+	case *dst.Ident:
+		if lit, ok := rh.tables[e.Name]; ok {
+			return lit, rh.curPkg, "", true
+		}
+		value, _ = rh.proj.GetGlobalValue(rh.curPkg, e.Name)
+	case *dst.SelectorExpr:
+		// qualifier maybe alias of import. e.g. r2 "example.com/api"
+		p, name, ok := rh.proj.ResolveIn(rh.file, common.ToStr(e))
+		if !ok {
+			return nil, "", "", false
+		}
+		value, _ = p.GetGlobalValue(name)
+		lit, ok := value.(*dst.CompositeLit)
+		return lit, p.Path, common.ToStr(e.X), ok
+	}
+	lit, ok := value.(*dst.CompositeLit)
+	return lit, rh.curPkg, "", ok
+}
+
+// structFields field names of struct in order. e.g. Route -> [Method Path Handler]
+func (rh *route) structFields(curPkg string, typ dst.Expr) (names []string) {
+	st, err := rh.proj.GetStruct(curPkg, common.ToStr(typ))
+	if err != nil {
+		return nil
+	}
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			// embedded. e.g. *pkg.Base -> Base
+			_, name := splitDot(strings.Trim(common.ToStr(field.Type), "*"))
+			names = append(names, name)
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// qualify identifier declared at package level of table in other package by qualifier of package.
+// e.g. listUsers -> api.listUsers, nil and true are kept
+func (rh *route) qualify(expr dst.Expr, tablePkg, qualifier string) dst.Expr {
+	ident, ok := expr.(*dst.Ident)
+	if !ok || len(qualifier) == 0 || !rh.proj.Declares(tablePkg, ident.Name) {
+		return expr
 	}
+	return &dst.SelectorExpr{X: dst.NewIdent(qualifier), Sel: dst.NewIdent(ident.Name)}
 }
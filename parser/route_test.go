@@ -1,9 +1,15 @@
 package parser
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/dave/dst"
+	"github.com/hocv/gin-swagger-gen/lib/common"
+	"github.com/hocv/gin-swagger-gen/lib/conf"
 	"github.com/hocv/gin-swagger-gen/lib/file"
+	"github.com/hocv/gin-swagger-gen/lib/scan"
 
 	"github.com/hocv/gin-swagger-gen/lib/proj"
 )
@@ -22,7 +28,7 @@ func TestParseRoute(t *testing.T) {
 		return
 	}
 
-	rh := newRoute(p, "Default", "")
+	rh := newRoute(p, "Default", &conf.Config{})
 	rh.Parse(f, dstFn)
 	if len(rh.Handles) != 7 {
 		t.Fatal()
@@ -43,9 +49,538 @@ func TestGroup(t *testing.T) {
 		return
 	}
 
-	rh := newRoute(p, "Default", "")
+	rh := newRoute(p, "Default", &conf.Config{})
 	rh.Parse(f, dstFn)
 	if len(rh.Handles) != 1 {
 		t.Fatal()
 	}
 }
+
+func TestMultipleRoutes(t *testing.T) {
+	code := `
+package test
+
+import "github.com/gin-gonic/gin"
+
+func route() {
+	g := gin.Default()
+	v1 := g.Group("/v1")
+	v1.GET("/user/:id", getUser)
+	v2 := g.Group("/v2")
+	v2.GET("/user/:id", getUser)
+	g.DELETE("/user", getUser)
+}
+
+func getUser(c *gin.Context) {
+	name := c.Query("name")
+	c.JSON(200, name)
+}
+`
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := file.New(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.proj.AddFile(f)
+	p.Parse(false)
+
+	decl, err := f.Func("getUser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"// @Summary getUser",
+		"// @Tags v1,v2",
+		"// @Produce json",
+		"// @Param id path string true \"id\"", // needed by paths of v1 and v2, not by /user
+		"// @Param name query string false \"name\"",
+		"// @Success 200 string string",
+		"// @Router /user [DELETE]",
+		"// @Router /v1/user/{id} [GET]",
+		"// @Router /v2/user/{id} [GET]",
+	}
+	if got := decl.Decs.Start.All(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("%v, want %v", got, want)
+	}
+
+	// operations keep params of their own route
+	sw := p.Swagger()
+	if op := sw.Paths["/v1/user/{id}"]["get"]; op == nil || len(op.Parameters) != 2 {
+		t.Fatalf("/v1/user/{id} should have path and query params: %+v", op)
+	}
+	if op := sw.Paths["/user"]["delete"]; op == nil || len(op.Parameters) != 1 || op.Parameters[0].In != "query" {
+		t.Fatalf("/user should have query param only: %+v", op)
+	}
+}
+
+func TestRouteMethods(t *testing.T) {
+	code := `
+package test
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func route() {
+	g := gin.Default()
+	g.Handle("PROPFIND", "/dav", dav)
+	g.Match([]string{http.MethodGet, "post", http.MethodTrace}, "/match", match)
+	g.Any("/any", any)
+}
+
+func dav(c *gin.Context) {}
+
+func match(c *gin.Context) {}
+
+func any(c *gin.Context) {}
+`
+	f, err := file.New(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := proj.New()
+	p.AddFile(f)
+	dstFn, err := f.Func("route")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rh := newRoute(p, "Default", &conf.Config{})
+	rh.Parse(f, dstFn)
+
+	methods := map[string][]string{}
+	for _, hdl := range rh.Handles {
+		route := hdl.Cmt.Route()
+		methods[route.RoutePath] = append(methods[route.RoutePath], route.RouteMethod)
+	}
+	// methods not accepted by swag are skipped with a warning
+	want := map[string][]string{
+		"/match": {"GET", "POST"},
+		"/any":   {"GET", "POST", "PUT", "PATCH", "HEAD", "OPTIONS", "DELETE"},
+	}
+	if !reflect.DeepEqual(methods, want) {
+		t.Fatalf("%v, want %v", methods, want)
+	}
+	if len(rh.Warnings) != 2 || !strings.Contains(rh.Warnings[0], "PROPFIND /dav") || !strings.Contains(rh.Warnings[1], "TRACE /match") {
+		t.Fatalf("warnings %v", rh.Warnings)
+	}
+}
+
+func TestStaticRoutes(t *testing.T) {
+	code := `
+package test
+
+import "github.com/gin-gonic/gin"
+
+func route() {
+	g := gin.Default()
+	g.Static("/assets", "./assets")
+	g.StaticFile("/favicon.ico", "./favicon.ico")
+}
+`
+	p, err := New(&conf.Config{StaticTag: "frontend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := file.New(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.proj.AddFile(f)
+	p.Parse(false)
+
+	sw := p.Swagger()
+	for path, params := range map[string]int{"/assets/{filepath}": 1, "/favicon.ico": 0} {
+		for _, method := range []string{"get", "head"} {
+			op := sw.Paths[path][method]
+			if op == nil {
+				t.Fatalf("no %s %s", method, path)
+			}
+			if len(op.Parameters) != params || op.Tags[0] != "frontend" || op.Produces[0] != "application/octet-stream" {
+				t.Fatalf("%s %s: %+v", method, path, op)
+			}
+		}
+	}
+}
+
+func TestMethodHandles(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com\n",
+		"controller/controller.go": `
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type User struct{}
+
+type Order struct{}
+
+type Handlers struct {
+	Order *Order
+}
+
+func NewUser() *User {
+	return &User{}
+}
+
+func (u *User) List(c *gin.Context) {}
+
+func (o *Order) List(c *gin.Context) {}
+`,
+		"router/router.go": `
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"example.com/controller"
+)
+
+func route(h *controller.Handlers) {
+	g := gin.Default()
+	uc := controller.NewUser()
+	g.GET("/users", uc.List)
+	g.GET("/orders", h.Order.List)
+	g.GET("/users2", controller.NewUser().List)
+}
+`,
+	}
+	dir := writeTree(t, files)
+
+	p := proj.New()
+	p.ScanDir(dir, scan.Options{})
+	ffs := p.GetFunc("example.com/router", "route")
+	if len(ffs) != 1 {
+		t.Fatal("route not found")
+	}
+	rh := newRoute(p, "Default", &conf.Config{})
+	for f, fnd := range ffs {
+		rh.Parse(f, fnd)
+	}
+
+	recvs := map[string]string{}
+	for _, hdl := range rh.Handles {
+		recvs[hdl.Cmt.Route().RoutePath] = common.ToStr(hdl.DstDecl.Recv.List[0].Type)
+	}
+	want := map[string]string{"/users": "*User", "/orders": "*Order", "/users2": "*User"}
+	if !reflect.DeepEqual(recvs, want) {
+		t.Fatalf("%v, want %v", recvs, want)
+	}
+}
+
+func TestFactoryHandles(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com\n",
+		"handlers/handlers.go": `
+package handlers
+
+import "github.com/gin-gonic/gin"
+
+type Service struct{}
+
+func CreateOrder(svc *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {}
+}
+
+func ListOrders(svc *Service) func(*gin.Context) {
+	return gin.HandlerFunc(func(c *gin.Context) {})
+}
+
+func Name(svc *Service) string {
+	return "orders"
+}
+`,
+		"router/router.go": `
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"example.com/handlers"
+)
+
+func route(svc *handlers.Service) {
+	g := gin.Default()
+	g.POST("/orders", handlers.CreateOrder(svc))
+	g.GET("/orders", handlers.ListOrders(svc))
+	g.GET("/name", handlers.Name(svc))
+}
+`,
+	}
+	dir := writeTree(t, files)
+
+	p := proj.New()
+	p.ScanDir(dir, scan.Options{})
+	ffs := p.GetFunc("example.com/router", "route")
+	if len(ffs) != 1 {
+		t.Fatal("route not found")
+	}
+	rh := newRoute(p, "Default", &conf.Config{})
+	for f, fnd := range ffs {
+		rh.Parse(f, fnd)
+	}
+
+	factories := map[string]string{}
+	for _, hdl := range rh.Handles {
+		factories[hdl.Cmt.Route().RouteMethod] = hdl.DstDecl.Name.Name
+		if params := common.GetFuncParams(hdl.SrcDecl); params["svc"] == "" || params["c"] == "" {
+			t.Fatalf("%s params %v", hdl.DstDecl.Name.Name, params)
+		}
+	}
+	want := map[string]string{"POST": "CreateOrder", "GET": "ListOrders"}
+	if !reflect.DeepEqual(factories, want) {
+		t.Fatalf("%v, want %v", factories, want)
+	}
+}
+
+func TestInlineHandles(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com\n",
+		"main.go": `
+package main
+
+import "github.com/gin-gonic/gin"
+
+func main() {
+	g := gin.Default()
+	v1 := g.Group("/v1")
+	g.GET("/ping", func(c *gin.Context) {
+		c.String(200, "pong")
+	})
+	v1.DELETE("/users/:id", func(c *gin.Context) {})
+	v1.DELETE("/users/id", func(c *gin.Context) {})
+}
+`,
+	}
+	dir := writeTree(t, files)
+
+	p := proj.New()
+	p.ScanDir(dir, scan.Options{})
+	ffs := p.GetFunc("example.com", "main")
+	if len(ffs) != 1 {
+		t.Fatal("main not found")
+	}
+	rh := newRoute(p, "Default", &conf.Config{})
+	for f, fnd := range ffs {
+		rh.Parse(f, fnd)
+	}
+
+	uniqueIDs(rh.Handles)
+	ids := map[string]string{}
+	for _, hdl := range rh.Handles {
+		if hdl.DstDecl != nil || hdl.SrcDecl == nil {
+			t.Fatalf("%s: decl of inline handle", hdl.Cmt.Route().RoutePath)
+		}
+		route := hdl.Cmt.Route()
+		if summary := route.RouteMethod + " " + route.RoutePath; hdl.Cmt.Summary() != summary {
+			t.Fatalf("summary %q, want %q", hdl.Cmt.Summary(), summary)
+		}
+		ids[route.RoutePath] = hdl.Cmt.ID()
+	}
+	want := map[string]string{"/ping": "getPing", "/v1/users/{id}": "deleteV1UsersId", "/v1/users/id": "deleteV1UsersId2"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("%v, want %v", ids, want)
+	}
+}
+
+func TestRoutePackages(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com\n",
+		"api/api.go": `
+package api
+
+import "github.com/gin-gonic/gin"
+
+func Register(r *gin.Engine) {
+	r.GET("/health", health)
+	admin := r.Group("/admin")
+	adminRoutes(admin)
+}
+
+func adminRoutes(g *gin.RouterGroup) {
+	g.GET("/stats", stats)
+}
+
+func health(c *gin.Context) {}
+
+func stats(c *gin.Context) {}
+`,
+		"v1/v1.go": `
+package v1
+
+import "github.com/gin-gonic/gin"
+
+func Routes(g *gin.RouterGroup) {
+	g.GET("/users", users)
+}
+
+func users(c *gin.Context) {}
+`,
+		"main.go": `
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"example.com/api"
+	routes "example.com/v1"
+)
+
+func main() {
+	r := gin.Default()
+	api.Register(r)
+	routes.Routes(r.Group("/v1"))
+	v2 := r.Group("/v2")
+	routes.Routes(v2)
+}
+`,
+	}
+	dir := writeTree(t, files)
+
+	p := proj.New()
+	p.ScanDir(dir, scan.Options{})
+	ffs := p.GetFunc("example.com", "main")
+	if len(ffs) != 1 {
+		t.Fatal("main not found")
+	}
+	rh := newRoute(p, "Default", &conf.Config{})
+	for f, fnd := range ffs {
+		rh.Parse(f, fnd)
+	}
+
+	routes := map[string]string{}
+	for _, hdl := range rh.Handles {
+		routes[hdl.Cmt.Route().RoutePath] = hdl.DstDecl.Name.Name
+	}
+	want := map[string]string{
+		"/health":      "health",
+		"/admin/stats": "stats",
+		"/v1/users":    "users",
+		"/v2/users":    "users",
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Fatalf("%v, want %v", routes, want)
+	}
+}
+
+func TestRouteTables(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com\n",
+		"api/api.go": `
+package api
+
+import "github.com/gin-gonic/gin"
+
+type Route struct {
+	Method  string
+	Path    string
+	Handler gin.HandlerFunc
+}
+
+var Routes = []Route{
+	{Method: "DELETE", Path: "/users/:id", Handler: deleteUser},
+}
+
+var Positional = []Route{
+	{"PATCH", "/users/:id", patchUser},
+}
+
+func deleteUser(c *gin.Context) {}
+
+func patchUser(c *gin.Context) {}
+`,
+		"main.go": `
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	r2 "example.com/api"
+)
+
+type route struct {
+	method, path string
+	handler      gin.HandlerFunc
+}
+
+var global = []*route{
+	{http.MethodPut, "/books", updateBook},
+}
+
+func main() {
+	g := gin.Default()
+	v1 := g.Group("/v1")
+	local := []route{
+		{"GET", "/books", listBooks},
+		{"POST", "/books", createBook},
+	}
+	for _, rt := range local {
+		v1.Handle(rt.method, rt.path, rt.handler)
+	}
+	for _, rt := range global {
+		g.Handle(rt.method, rt.path, rt.handler)
+	}
+	index := []route{{"GET", "/index", ping}}
+	for i := range index {
+		g.Handle(index[i].method, index[i].path, index[i].handler)
+	}
+	for path, h := range map[string]gin.HandlerFunc{"/ping": ping} {
+		g.GET(path, h)
+	}
+	for _, rt := range r2.Routes {
+		g.Handle(rt.Method, rt.Path, rt.Handler)
+	}
+	for _, rt := range r2.Positional {
+		g.Handle(rt.Method, rt.Path, rt.Handler)
+	}
+}
+
+func listBooks(c *gin.Context) {}
+
+func createBook(c *gin.Context) {}
+
+func updateBook(c *gin.Context) {}
+
+func ping(c *gin.Context) {}
+`,
+	}
+	dir := writeTree(t, files)
+
+	p := proj.New()
+	p.ScanDir(dir, scan.Options{})
+	ffs := p.GetFunc("example.com", "main")
+	if len(ffs) != 1 {
+		t.Fatal("main not found")
+	}
+	rh := newRoute(p, "Default", &conf.Config{})
+	for f, fnd := range ffs {
+		rh.Parse(f, fnd)
+	}
+
+	routes := map[string]string{}
+	for _, hdl := range rh.Handles {
+		route := hdl.Cmt.Route()
+		routes[route.RouteMethod+" "+route.RoutePath] = hdl.DstDecl.Name.Name
+	}
+	want := map[string]string{
+		"GET /v1/books":      "listBooks",
+		"POST /v1/books":     "createBook",
+		"PUT /books":         "updateBook",
+		"GET /ping":          "ping",
+		"GET /index":         "ping",
+		"DELETE /users/{id}": "deleteUser",
+		"PATCH /users/{id}":  "patchUser",
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Fatalf("%v, want %v", routes, want)
+	}
+
+	// only identifiers declared in package of table are qualified
+	for name, want := range map[string]string{"patchUser": "r2.patchUser", "Routes": "r2.Routes", "nil": "nil", "true": "true"} {
+		if got := common.ToStr(rh.qualify(dst.NewIdent(name), "example.com/api", "r2")); got != want {
+			t.Fatalf("qualify %s: %s, want %s", name, got, want)
+		}
+	}
+}
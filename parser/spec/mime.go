@@ -0,0 +1,42 @@
+package spec
+
+import "strings"
+
+// mimeAlias alias of mime type used in comment
+var mimeAlias = map[string]string{
+	"json":                  "application/json",
+	"xml":                   "application/xml",
+	"yaml":                  "application/x-yaml",
+	"plain":                 "text/plain",
+	"string":                "text/plain",
+	"html":                  "text/html",
+	"js":                    "application/javascript",
+	"protobuf":              "application/x-protobuf",
+	"msgpack":               "application/x-msgpack",
+	"mpfd":                  "multipart/form-data",
+	"x-www-form-urlencoded": "application/x-www-form-urlencoded",
+	"json-api":              "application/vnd.api+json",
+	"json-stream":           "application/x-json-stream",
+	"octet-stream":          "application/octet-stream",
+	"png":                   "image/png",
+	"jpeg":                  "image/jpeg",
+	"gif":                   "image/gif",
+}
+
+// MimeType convert alias to mime type.
+// e.g. json => application/json
+func MimeType(alias string) string {
+	alias = strings.TrimSpace(alias)
+	if mt, ok := mimeAlias[alias]; ok {
+		return mt
+	}
+	return alias
+}
+
+// MimeTypes convert aliases to mime types
+func MimeTypes(aliases []string) (mts []string) {
+	for _, a := range aliases {
+		mts = append(mts, MimeType(a))
+	}
+	return
+}
@@ -0,0 +1,94 @@
+package spec
+
+// OpenAPI OpenAPI 3.x document
+type OpenAPI struct {
+	OpenAPI    string                     `json:"openapi" yaml:"openapi"`
+	Info       Info                       `json:"info" yaml:"info"`
+	Servers    []Server                   `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Paths      map[string]OpenAPIPathItem `json:"paths" yaml:"paths"`
+	Components Components                 `json:"components" yaml:"components"`
+}
+
+// Server api server
+type Server struct {
+	URL string `json:"url" yaml:"url"`
+}
+
+// Components reusable objects
+type Components struct {
+	Schemas         map[string]*Schema                `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	SecuritySchemes map[string]*OpenAPISecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+// OpenAPIPathItem operations of path, key: lower case method
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+// OpenAPIOperation api operation
+type OpenAPIOperation struct {
+	Tags        []string                   `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string                     `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                     `json:"description,omitempty" yaml:"description,omitempty"`
+	OperationID string                     `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Parameters  []*OpenAPIParameter        `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody               `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses" yaml:"responses"`
+}
+
+// OpenAPIParameter parameter in path, query, header or cookie
+type OpenAPIParameter struct {
+	Name        string  `json:"name" yaml:"name"`
+	In          string  `json:"in" yaml:"in"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool    `json:"required" yaml:"required"`
+	Schema      *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// RequestBody request body
+type RequestBody struct {
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool                 `json:"required" yaml:"required"`
+	Content     map[string]MediaType `json:"content" yaml:"content"`
+}
+
+// OpenAPIResponse operation response
+type OpenAPIResponse struct {
+	Description string               `json:"description" yaml:"description"`
+	Content     map[string]MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// MediaType schema of content type
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// ComponentsRefPrefix prefix of reference to components schemas
+const ComponentsRefPrefix = "#/components/schemas/"
+
+// NewOpenAPI empty document, version: 3.0.x or 3.1.x
+func NewOpenAPI(version string) *OpenAPI {
+	return &OpenAPI{
+		OpenAPI: version,
+		Paths:   map[string]OpenAPIPathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+	}
+}
+
+// AddOperation add operation to path with method, false if method is not supported. e.g. connect,
+// or path has operation of method already, which is kept
+func (oa *OpenAPI) AddOperation(path, method string, op *OpenAPIOperation) bool {
+	if !swaggerMethods[method] && method != "trace" {
+		return false
+	}
+	item, ok := oa.Paths[path]
+	if !ok {
+		item = OpenAPIPathItem{}
+		oa.Paths[path] = item
+	}
+	if _, ok := item[method]; ok {
+		return false
+	}
+	item[method] = op
+	return true
+}
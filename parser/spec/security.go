@@ -0,0 +1,30 @@
+package spec
+
+// SecurityScheme security definition of OpenAPI 2.0
+type SecurityScheme struct {
+	Type             string            `json:"type" yaml:"type"`
+	Description      string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Name             string            `json:"name,omitempty" yaml:"name,omitempty"`
+	In               string            `json:"in,omitempty" yaml:"in,omitempty"`
+	Flow             string            `json:"flow,omitempty" yaml:"flow,omitempty"`
+	AuthorizationURL string            `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+}
+
+// OpenAPISecurityScheme security scheme of OpenAPI 3
+type OpenAPISecurityScheme struct {
+	Type        string                `json:"type" yaml:"type"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Name        string                `json:"name,omitempty" yaml:"name,omitempty"`
+	In          string                `json:"in,omitempty" yaml:"in,omitempty"`
+	Scheme      string                `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	Flows       map[string]*OAuthFlow `json:"flows,omitempty" yaml:"flows,omitempty"`
+}
+
+// OAuthFlow oauth2 flow of OpenAPI 3, key in flows: implicit, password, clientCredentials, authorizationCode
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes" yaml:"scopes"`
+}
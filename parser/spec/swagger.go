@@ -0,0 +1,117 @@
+package spec
+
+// Swagger OpenAPI 2.0 document
+type Swagger struct {
+	Swagger     string              `json:"swagger" yaml:"swagger"`
+	Info        Info                `json:"info" yaml:"info"`
+	Host        string              `json:"host,omitempty" yaml:"host,omitempty"`
+	BasePath    string              `json:"basePath,omitempty" yaml:"basePath,omitempty"`
+	Paths       map[string]PathItem `json:"paths" yaml:"paths"`
+	Definitions map[string]*Schema  `json:"definitions,omitempty" yaml:"definitions,omitempty"`
+
+	SecurityDefinitions map[string]*SecurityScheme `json:"securityDefinitions,omitempty" yaml:"securityDefinitions,omitempty"`
+}
+
+// Info api info
+type Info struct {
+	Title       string   `json:"title" yaml:"title"`
+	Version     string   `json:"version" yaml:"version"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Contact     *Contact `json:"contact,omitempty" yaml:"contact,omitempty"`
+	License     *License `json:"license,omitempty" yaml:"license,omitempty"`
+}
+
+// Contact contact info
+type Contact struct {
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	URL   string `json:"url,omitempty" yaml:"url,omitempty"`
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+}
+
+// License license info
+type License struct {
+	Name string `json:"name" yaml:"name"`
+	URL  string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// PathItem operations of path, key: lower case method
+type PathItem map[string]*Operation
+
+// Operation api operation
+type Operation struct {
+	Tags        []string            `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string              `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string              `json:"description,omitempty" yaml:"description,omitempty"`
+	OperationID string              `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Consumes    []string            `json:"consumes,omitempty" yaml:"consumes,omitempty"`
+	Produces    []string            `json:"produces,omitempty" yaml:"produces,omitempty"`
+	Parameters  []*Parameter        `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Responses   map[string]Response `json:"responses" yaml:"responses"`
+}
+
+// Parameter operation parameter
+type Parameter struct {
+	Name        string  `json:"name" yaml:"name"`
+	In          string  `json:"in" yaml:"in"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool    `json:"required" yaml:"required"`
+	Type        string  `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string  `json:"format,omitempty" yaml:"format,omitempty"`
+	Items       *Schema `json:"items,omitempty" yaml:"items,omitempty"`
+	Schema      *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// Response operation response
+type Response struct {
+	Description string  `json:"description" yaml:"description"`
+	Schema      *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// Schema json schema
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Description          string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	ContentMediaType     string             `json:"contentMediaType,omitempty" yaml:"contentMediaType,omitempty"`
+}
+
+// RefPrefix prefix of reference to definitions
+const RefPrefix = "#/definitions/"
+
+// NewSwagger empty document
+func NewSwagger() *Swagger {
+	return &Swagger{
+		Swagger:     "2.0",
+		Paths:       map[string]PathItem{},
+		Definitions: map[string]*Schema{},
+	}
+}
+
+// swaggerMethods methods of path item in OpenAPI 2.0
+var swaggerMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true,
+}
+
+// AddOperation add operation to path with method, false if method is not supported. e.g. connect,
+// or path has operation of method already, which is kept
+func (sw *Swagger) AddOperation(path, method string, op *Operation) bool {
+	if !swaggerMethods[method] {
+		return false
+	}
+	item, ok := sw.Paths[path]
+	if !ok {
+		item = PathItem{}
+		sw.Paths[path] = item
+	}
+	if _, ok := item[method]; ok {
+		return false
+	}
+	item[method] = op
+	return true
+}
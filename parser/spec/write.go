@@ -0,0 +1,33 @@
+package spec
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Marshal marshal document to json, or yaml if ext is .yaml or .yml
+func Marshal(doc interface{}, ext string) ([]byte, error) {
+	switch ext {
+	case ".yaml", ".yml":
+		return yaml.Marshal(doc)
+	default:
+		return json.MarshalIndent(doc, "", "    ")
+	}
+}
+
+// Write write document to file, format depends on file ext.
+// dir of file is created if not exists
+func Write(doc interface{}, path string) error {
+	bs, err := Marshal(doc, filepath.Ext(path))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bs, 0666)
+}
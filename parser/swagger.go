@@ -0,0 +1,331 @@
+package parser
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dave/dst"
+	"github.com/hocv/gin-swagger-gen/lib/common"
+	"github.com/hocv/gin-swagger-gen/lib/proj"
+	"github.com/hocv/gin-swagger-gen/parser/comment"
+	"github.com/hocv/gin-swagger-gen/parser/spec"
+)
+
+// primitiveSchema go type or swagger type to schema type and format
+var primitiveSchema = map[string][2]string{
+	"string":      {"string", ""},
+	"char":        {"string", ""},
+	"error":       {"string", ""},
+	"bool":        {"boolean", ""},
+	"boolean":     {"boolean", ""},
+	"int":         {"integer", ""},
+	"int8":        {"integer", ""},
+	"int16":       {"integer", ""},
+	"int32":       {"integer", "int32"},
+	"int64":       {"integer", "int64"},
+	"uint":        {"integer", ""},
+	"uint8":       {"integer", ""},
+	"uint16":      {"integer", ""},
+	"uint32":      {"integer", "int32"},
+	"uint64":      {"integer", "int64"},
+	"byte":        {"integer", ""},
+	"rune":        {"integer", "int32"},
+	"integer":     {"integer", ""},
+	"float":       {"number", ""},
+	"float32":     {"number", "float"},
+	"float64":     {"number", "double"},
+	"number":      {"number", ""},
+	"file":        {"file", ""},
+	"time.Time":   {"string", "date-time"},
+	"interface{}": {"object", ""},
+	"any":         {"object", ""},
+	"object":      {"object", ""},
+}
+
+// Swagger build OpenAPI 2.0 document from parsed handles
+func (parser *Parser) Swagger() *spec.Swagger {
+	sw := spec.NewSwagger()
+	gi := parser.apiInfo()
+	sw.Info = gi.swaggerInfo()
+	sw.Host = gi.attrs[host]
+	sw.BasePath = gi.attrs[basePath]
+	for _, s := range gi.security {
+		if sw.SecurityDefinitions == nil {
+			sw.SecurityDefinitions = map[string]*spec.SecurityScheme{}
+		}
+		sw.SecurityDefinitions[s.name] = s.swagger()
+	}
+
+	sb := newSchemaBuilder(parser.proj, sw.Definitions, spec.RefPrefix)
+	for _, hdl := range parser.hdls {
+		route := hdl.Cmt.Route()
+		sw.AddOperation(route.RoutePath, strings.ToLower(route.RouteMethod), sb.operation(hdl.curPkg, hdl.Cmt))
+	}
+	return sw
+}
+
+// schemaBuilder build schema from type string, and collect definitions
+type schemaBuilder struct {
+	proj      *proj.Proj
+	defs      map[string]*spec.Schema
+	defPaths  map[string]string // key: definition name, value: import path of package
+	refPrefix string
+}
+
+func newSchemaBuilder(proj *proj.Proj, defs map[string]*spec.Schema, refPrefix string) *schemaBuilder {
+	return &schemaBuilder{
+		proj:      proj,
+		defs:      defs,
+		defPaths:  map[string]string{},
+		refPrefix: refPrefix,
+	}
+}
+
+func (sb *schemaBuilder) operation(curPkg string, cmt *comment.Comment) *spec.Operation {
+	op := &spec.Operation{
+		Tags:        cmt.Tags(),
+		Summary:     cmt.Summary(),
+		Description: cmt.Description(),
+		OperationID: cmt.ID(),
+		Consumes:    spec.MimeTypes(cmt.Accept()),
+		Produces:    spec.MimeTypes(cmt.Produce()),
+		Responses:   map[string]spec.Response{},
+	}
+
+	for _, p := range cmt.Params() {
+		op.Parameters = append(op.Parameters, sb.parameter(curPkg, p))
+	}
+
+	for _, r := range cmt.Resps() {
+		resp := spec.Response{
+			Description: http.StatusText(r.Code),
+			Schema:      sb.schema(curPkg, r.Type),
+		}
+		op.Responses[strconv.Itoa(r.Code)] = resp
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["default"] = spec.Response{Description: "default"}
+	}
+	return op
+}
+
+func (sb *schemaBuilder) parameter(curPkg string, p comment.Param) *spec.Parameter {
+	param := &spec.Parameter{
+		Name:        p.Name,
+		In:          p.In(),
+		Description: p.Description,
+		Required:    p.Required,
+	}
+
+	s := sb.schema(curPkg, p.RefType)
+	if param.In == "body" {
+		param.Schema = s
+		return param
+	}
+
+	switch s.Type {
+	case "", "object":
+		param.Type = "string"
+	default:
+		param.Type = s.Type
+		param.Format = s.Format
+		param.Items = s.Items
+	}
+	if len(p.Format) > 0 {
+		param.Format = p.Format
+	}
+	return param
+}
+
+// schema type string to schema.
+// e.g. []book.Book, map[string]int, Resp{data=book.Book}
+func (sb *schemaBuilder) schema(curPkg, typ string) *spec.Schema {
+	typ = strings.TrimLeft(strings.TrimSpace(typ), "*")
+
+	if ps, ok := primitiveSchema[typ]; ok {
+		return &spec.Schema{Type: ps[0], Format: ps[1]}
+	}
+
+	if strings.HasPrefix(typ, "[]") {
+		if typ == "[]byte" {
+			return &spec.Schema{Type: "string", Format: "byte"}
+		}
+		return &spec.Schema{
+			Type:  "array",
+			Items: sb.schema(curPkg, typ[2:]),
+		}
+	}
+
+	if strings.HasPrefix(typ, "map[") {
+		idx := closeIndex(typ, len("map"))
+		if idx < 0 {
+			return &spec.Schema{Type: "object"}
+		}
+		return &spec.Schema{
+			Type:                 "object",
+			AdditionalProperties: sb.schema(curPkg, typ[idx+1:]),
+		}
+	}
+
+	// composition. e.g. Resp{data=book.Book}
+	if idx := strings.Index(typ, "{"); idx > 0 && strings.HasSuffix(typ, "}") {
+		base := sb.schema(curPkg, typ[:idx])
+		over := &spec.Schema{Type: "object"}
+		for _, kv := range splitTop(typ[idx+1:len(typ)-1], ',') {
+			i := strings.Index(kv, "=")
+			if i < 1 {
+				continue
+			}
+			setProperty(over, strings.Split(kv[:i], "."), sb.schema(curPkg, kv[i+1:]))
+		}
+		if len(over.Properties) == 0 {
+			return base
+		}
+		return &spec.Schema{AllOf: []*spec.Schema{base, over}}
+	}
+
+	return sb.named(curPkg, typ)
+}
+
+// named struct or type defined in project
+func (sb *schemaBuilder) named(curPkg, typ string) *spec.Schema {
+	p, name, ok := sb.proj.Resolve(curPkg, typ)
+	if !ok {
+		return &spec.Schema{Type: "object"}
+	}
+
+	stru, err := p.GetStruct(name)
+	if err != nil {
+		ut, err := p.GetType(name)
+		if err != nil || ut == name {
+			return &spec.Schema{Type: "object"}
+		}
+		return sb.schema(p.Path, ut)
+	}
+
+	defName := p.Name + "." + name
+	// packages with same name, use import path
+	if path, ok := sb.defPaths[defName]; ok && path != p.Path {
+		defName = strings.ReplaceAll(p.Path, "/", "_") + "." + name
+	}
+	sb.defPaths[defName] = p.Path
+	if _, ok := sb.defs[defName]; !ok {
+		def := &spec.Schema{Type: "object"}
+		sb.defs[defName] = def
+		sb.addFields(def, p.Path, stru)
+	}
+	return &spec.Schema{Ref: sb.refPrefix + defName}
+}
+
+// addFields add fields of struct to schema properties
+func (sb *schemaBuilder) addFields(s *spec.Schema, curPkg string, stru *dst.StructType) {
+	for _, field := range stru.Fields.List {
+		var tag, tagName string
+		if field.Tag != nil {
+			tag = field.Tag.Value
+			tagName = common.GetJsonTag(tag)
+		}
+		if tagName == "-" {
+			continue
+		}
+
+		// embedded struct, fields are promoted
+		if len(field.Names) == 0 && len(tagName) == 0 {
+			p, name, ok := sb.proj.Resolve(curPkg, common.ToStr(field.Type))
+			if !ok {
+				continue
+			}
+			if es, err := p.GetStruct(name); err == nil {
+				sb.addFields(s, p.Path, es)
+			}
+			continue
+		}
+
+		fs := sb.fieldSchema(curPkg, field.Type)
+		names := []string{tagName}
+		if len(tagName) == 0 {
+			names = names[:0]
+			for _, ident := range field.Names {
+				names = append(names, ident.Name)
+			}
+		}
+		for _, n := range names {
+			if s.Properties == nil {
+				s.Properties = map[string]*spec.Schema{}
+			}
+			s.Properties[n] = fs
+			if common.GetTagBindingRequired(tag) {
+				s.Required = append(s.Required, n)
+			}
+		}
+	}
+}
+
+func (sb *schemaBuilder) fieldSchema(curPkg string, expr dst.Expr) *spec.Schema {
+	switch t := expr.(type) {
+	case *dst.StructType:
+		s := &spec.Schema{Type: "object"}
+		sb.addFields(s, curPkg, t)
+		return s
+	case *dst.InterfaceType:
+		return &spec.Schema{Type: "object"}
+	}
+	return sb.schema(curPkg, common.ToStr(expr))
+}
+
+// setProperty set property by keys path, e.g. [data res]
+func setProperty(s *spec.Schema, keys []string, val *spec.Schema) {
+	if s.Properties == nil {
+		s.Properties = map[string]*spec.Schema{}
+	}
+	if len(keys) == 1 {
+		s.Properties[keys[0]] = val
+		return
+	}
+	child, ok := s.Properties[keys[0]]
+	if !ok || child.Type != "object" {
+		child = &spec.Schema{Type: "object"}
+		s.Properties[keys[0]] = child
+	}
+	setProperty(child, keys[1:], val)
+}
+
+// closeIndex index of bracket closing the one at start
+func closeIndex(str string, start int) int {
+	depth := 0
+	for i := start; i < len(str); i++ {
+		switch str[i] {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTop split string by sep, ignore sep in brackets
+func splitTop(str string, sep byte) (arr []string) {
+	depth, last := 0, 0
+	for i := 0; i < len(str); i++ {
+		switch str[i] {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		case sep:
+			if depth == 0 {
+				arr = append(arr, str[last:i])
+				last = i + 1
+			}
+		}
+	}
+	if last < len(str) {
+		arr = append(arr, str[last:])
+	}
+	return
+}
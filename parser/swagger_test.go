@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hocv/gin-swagger-gen/lib/conf"
+	"github.com/hocv/gin-swagger-gen/parser/spec"
+)
+
+func TestSwagger(t *testing.T) {
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ScanDir("./test/main")
+	p.Parse(true)
+
+	sw := p.Swagger()
+	op, ok := sw.Paths["/g/get"]["get"]
+	if !ok {
+		t.Fatal("operation not found")
+	}
+	if len(op.Parameters) != 2 {
+		t.Fatalf("params size wrong: %d", len(op.Parameters))
+	}
+	if _, ok := op.Responses["200"]; !ok {
+		t.Fatal("response 200 not found")
+	}
+	for _, def := range []string{"main.Resp", "main.User"} {
+		if _, ok := sw.Definitions[def]; !ok {
+			t.Fatalf("definition %s not found", def)
+		}
+	}
+}
+
+func TestSchemaComposition(t *testing.T) {
+	sb := newSchemaBuilder(nil, nil, spec.RefPrefix)
+	arr := splitTop("data=[]book.Book,data2=Resp{data=map[string]int,code=int}", ',')
+	if len(arr) != 2 {
+		t.Fatalf("split size wrong: %v", arr)
+	}
+	s := sb.schema("", "[]map[string]int")
+	if s.Type != "array" || s.Items.AdditionalProperties.Type != "integer" {
+		t.Fatal("schema wrong")
+	}
+}
+
+func TestDuplicateRoutes(t *testing.T) {
+	dir := writeTree(t, map[string]string{
+		"go.mod": "module example.com\n",
+		"main.go": `
+package main
+
+import "github.com/gin-gonic/gin"
+
+func main() {
+	r := gin.Default()
+	r.GET("/user", getUser)
+}
+
+func admin() {
+	r := gin.Default()
+	r.GET("/user", getAdmin)
+}
+
+func getUser(c *gin.Context) {
+	c.JSON(200, "user")
+}
+
+func getAdmin(c *gin.Context) {
+	c.JSON(200, "admin")
+}
+`,
+	})
+	p, err := New(&conf.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ScanDir(dir)
+	p.Parse(true)
+
+	warnings := p.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "GET /user: duplicate route") {
+		t.Fatalf("warnings %v", warnings)
+	}
+	// operation of first handle is kept
+	if op := p.Swagger().Paths["/user"]["get"]; op == nil || op.Summary != "getUser" {
+		t.Fatalf("operation %+v", op)
+	}
+	if op := p.OpenAPI("3.0").Paths["/user"]["get"]; op == nil || op.Summary != "getUser" {
+		t.Fatalf("operation %+v", op)
+	}
+}
@@ -1,10 +1,14 @@
 package test
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/hocv/gin-swagger-gen/parser/test/model/book"
 	"github.com/hocv/gin-swagger-gen/parser/test/model/price"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 func handleTest() {
@@ -13,6 +17,13 @@ func handleTest() {
 	group := g.Group("/group")
 	group.GET("/hdl_accept", handleAccept)
 	group.GET("/hdl_product", handleProduct)
+	group.GET("/hdl_header", handleHeader)
+	group.GET("/hdl_path/:id/:page", handlePath)
+	group.GET("/hdl_uri/:uid", handleUri)
+	group.GET("/hdl_bind", handleBind)
+	group.POST("/hdl_bind", handleBind)
+	group.POST("/hdl_bind_with", handleBindWith)
+	group.POST("/hdl_wrapper", handleWrapper)
 
 	_ = g.Run(":9090")
 }
@@ -39,6 +50,64 @@ func handleAccept(c *gin.Context) {
 	_ = c.BindQuery(&b3)
 }
 
+type auth struct {
+	Token   string `header:"Authorization" binding:"required"`
+	TraceID string `header:"X-Trace-ID"`
+}
+
+func handleHeader(c *gin.Context) {
+	tenant := c.GetHeader("X-Tenant-ID")
+	lang := c.Request.Header.Get("Accept-Language")
+	var a auth
+	_ = c.ShouldBindHeader(&a)
+	c.String(200, tenant+lang+a.Token)
+}
+
+func handlePath(c *gin.Context) {
+	id := c.Param("id")
+	i, _ := strconv.Atoi(id)
+	page, _ := strconv.Atoi(c.Param("page"))
+	c.String(200, fmt.Sprint(i, page))
+}
+
+type uri struct {
+	UID string `uri:"uid" binding:"required,uuid"`
+}
+
+func handleUri(c *gin.Context) {
+	var u uri
+	_ = c.ShouldBindUri(&u)
+	c.String(200, u.UID)
+}
+
+type search struct {
+	Keyword string `form:"kw" binding:"required"`
+	Page    int
+}
+
+func handleBind(c *gin.Context) {
+	var s search
+	_ = c.ShouldBind(&s)
+	c.JSON(200, s)
+}
+
+func handleBindWith(c *gin.Context) {
+	var s search
+	_ = c.ShouldBindWith(&s, binding.FormMultipart)
+	var p price.Price
+	_ = c.ShouldBindBodyWith(&p, binding.YAML)
+	c.JSON(200, s)
+}
+
+func respond(c *gin.Context, code int, data interface{}) {
+	c.Status(code)
+}
+
+func handleWrapper(c *gin.Context) {
+	b := book.Book{}
+	respond(c, 201, b)
+}
+
 func getBook() (book.Book, error) {
 	return book.Book{}, nil
 }
@@ -0,0 +1,50 @@
+package typed
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type User struct {
+	Name string `json:"name"`
+}
+
+type Order struct {
+	ID int `json:"id"`
+}
+
+type userCtrl struct{}
+
+type orderCtrl struct{}
+
+func (userCtrl) Create(c *gin.Context) {
+	var u User
+	if err := c.BindJSON(&u); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, u)
+}
+
+func (orderCtrl) Create(c *gin.Context) {
+	o := newOrder()
+	if err := c.BindJSON(o); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, o)
+}
+
+func newOrder() *Order {
+	return &Order{}
+}
+
+func route() {
+	r := gin.Default()
+	var uc userCtrl
+	var oc orderCtrl
+	r.POST("/user", uc.Create)
+	r.POST("/order", oc.Create)
+	_ = r.Run(":9090")
+}